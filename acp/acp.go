@@ -0,0 +1,184 @@
+package acp
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/ququzone/ckb-sdk-go/crypto"
+	"github.com/ququzone/ckb-sdk-go/transaction"
+	"github.com/ququzone/ckb-sdk-go/types"
+	"github.com/ququzone/ckb-sdk-go/utils"
+)
+
+var (
+	ErrUnknownNetwork      = errors.New("anyone-can-pay lock is not deployed on network")
+	ErrNotAnyoneCanPayCell = errors.New("cell is not locked by anyone-can-pay lock")
+	ErrNotUDTCell          = errors.New("cell is not a simple UDT cell")
+	ErrUnknownTypeScript   = errors.New("cell dep of type script is unknown")
+	ErrInvalidArgs         = errors.New("invalid anyone-can-pay args")
+	ErrBelowMinimum        = errors.New("amount is below anyone-can-pay minimum")
+)
+
+// Args of anyone-can-pay lock: blake160 of public key followed by optional exponents of minimum CKB and UDT amount,
+// a top up must transfer at least 10^exponent shannons or UDT base units.
+type Args struct {
+	PubKeyHash []byte
+	MinimumCKB *uint8
+	MinimumUDT *uint8
+}
+
+func ParseArgs(args []byte) (*Args, error) {
+	if len(args) < 20 || len(args) > 22 {
+		return nil, fmt.Errorf("%w: length %d", ErrInvalidArgs, len(args))
+	}
+	result := &Args{
+		PubKeyHash: args[:20],
+	}
+	if len(args) > 20 {
+		result.MinimumCKB = &args[20]
+	}
+	if len(args) > 21 {
+		result.MinimumUDT = &args[21]
+	}
+	return result, nil
+}
+
+func (a *Args) Bytes() []byte {
+	args := append([]byte{}, a.PubKeyHash...)
+	if a.MinimumCKB != nil {
+		args = append(args, *a.MinimumCKB)
+		if a.MinimumUDT != nil {
+			args = append(args, *a.MinimumUDT)
+		}
+	}
+	return args
+}
+
+// MinimumCKBAmount returns minimum shannons of a top up, 0 when not limited
+func (a *Args) MinimumCKBAmount() uint64 {
+	if a.MinimumCKB == nil {
+		return 0
+	}
+	// 10^20 overflows uint64, no top up can reach it
+	if *a.MinimumCKB >= 20 {
+		return math.MaxUint64
+	}
+	amount := uint64(1)
+	for i := uint8(0); i < *a.MinimumCKB; i++ {
+		amount *= 10
+	}
+	return amount
+}
+
+// MinimumUDTAmount returns minimum UDT amount of a top up, nil when not limited
+func (a *Args) MinimumUDTAmount() *big.Int {
+	if a.MinimumUDT == nil {
+		return nil
+	}
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(*a.MinimumUDT)), nil)
+}
+
+// TopUp transfers CKB into an existing anyone-can-pay cell, inputs paying the amount are locked by
+// secp256k1 or anyone-can-pay lock of the payer and signed the same way.
+type TopUp struct {
+	Transaction *types.Transaction
+	cell        *types.Cell
+	group       []int
+	witnessArgs *types.WitnessArgs
+}
+
+// NewTopUp spends cell and recreates it with amount more capacity, data is the cell data which is kept unchanged.
+// The type script of cell is kept too and its cell dep is added, only simple UDT is supported.
+func NewTopUp(scripts *utils.SystemScripts, cell *types.Cell, data []byte, amount uint64) (*TopUp, error) {
+	if scripts.ACPCell == nil {
+		return nil, ErrUnknownNetwork
+	}
+	if cell.Lock == nil || cell.Lock.CodeHash != scripts.ACPCell.CellHash || cell.Lock.HashType != types.HashTypeType {
+		return nil, ErrNotAnyoneCanPayCell
+	}
+	args, err := ParseArgs(cell.Lock.Args)
+	if err != nil {
+		return nil, err
+	}
+	if minimum := args.MinimumCKBAmount(); amount < minimum {
+		return nil, fmt.Errorf("%w: %d is less than %d", ErrBelowMinimum, amount, minimum)
+	}
+
+	tx := NewAnyoneCanPayTx(scripts)
+	if cell.Type != nil {
+		dep, err := typeCellDep(scripts, cell.Type)
+		if err != nil {
+			return nil, err
+		}
+		tx.CellDeps = append(tx.CellDeps, dep)
+	}
+	tx.Inputs = append(tx.Inputs, &types.CellInput{
+		Since: 0,
+		PreviousOutput: &types.OutPoint{
+			TxHash: cell.OutPoint.TxHash,
+			Index:  cell.OutPoint.Index,
+		},
+	})
+	// receiver does not sign, capacity only increases
+	tx.Witnesses = append(tx.Witnesses, []byte{})
+	tx.Outputs = append(tx.Outputs, &types.CellOutput{
+		Capacity: cell.Capacity + amount,
+		Lock:     cell.Lock,
+		Type:     cell.Type,
+	})
+	tx.OutputsData = append(tx.OutputsData, data)
+
+	return &TopUp{
+		Transaction: tx,
+		cell:        cell,
+	}, nil
+}
+
+// NewAnyoneCanPayTx returns a transaction with cell deps of secp256k1 and anyone-can-pay locks
+func NewAnyoneCanPayTx(scripts *utils.SystemScripts) *types.Transaction {
+	tx := transaction.NewSecp256k1SingleSigTx(scripts)
+	if scripts.ACPCell != nil {
+		tx.CellDeps = append(tx.CellDeps, &types.CellDep{
+			OutPoint: scripts.ACPCell.OutPoint,
+			DepType:  types.DepTypeDepGroup,
+		})
+	}
+	return tx
+}
+
+// typeCellDep returns the cell dep of the type script of an anyone-can-pay cell, only simple UDT is known
+func typeCellDep(scripts *utils.SystemScripts, typeScript *types.Script) (*types.CellDep, error) {
+	if scripts.SUDTCell == nil || typeScript.CodeHash != scripts.SUDTCell.CellHash || typeScript.HashType != types.HashTypeType {
+		return nil, fmt.Errorf("%w: code hash %s", ErrUnknownTypeScript, typeScript.CodeHash.String())
+	}
+	return &types.CellDep{
+		OutPoint: scripts.SUDTCell.OutPoint,
+		DepType:  types.DepTypeCode,
+	}, nil
+}
+
+// Build collects payer cells for amount and fee, the remaining capacity goes to changeLock
+func (t *TopUp) Build(collector *utils.CellCollector, changeLock *types.Script, feeRate uint64) (*types.Transaction, error) {
+	builder := transaction.NewBuilder(t.Transaction, collector, changeLock, feeRate)
+	builder.InputCapacity = t.cell.Capacity
+	group, witnessArgs, err := builder.Build()
+	if err != nil {
+		return nil, err
+	}
+	t.group = group
+	t.witnessArgs = witnessArgs
+	return t.Transaction, nil
+}
+
+func (t *TopUp) Sign(key crypto.Key) (*types.Transaction, error) {
+	if len(t.group) == 0 {
+		return nil, errors.New("must build transaction first")
+	}
+	err := transaction.SingleSignTransaction(t.Transaction, t.group, t.witnessArgs, key)
+	if err != nil {
+		return nil, fmt.Errorf("sign transaction error: %v", err)
+	}
+	return t.Transaction, nil
+}
@@ -0,0 +1,208 @@
+package acp
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ququzone/ckb-sdk-go/transaction"
+	"github.com/ququzone/ckb-sdk-go/types"
+	"github.com/ququzone/ckb-sdk-go/udt"
+	"github.com/ququzone/ckb-sdk-go/utils"
+)
+
+func testACPCell(args string) *types.Cell {
+	return &types.Cell{
+		Capacity: 14200000000,
+		Lock: &types.Script{
+			CodeHash: types.HexToHash(transaction.ANYONE_CAN_PAY_TYPE_HASH_TESTNET),
+			HashType: types.HashTypeType,
+			Args:     common.FromHex(args),
+		},
+		OutPoint: &types.OutPoint{TxHash: types.HexToHash("0x0a"), Index: 0},
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	args, err := ParseArgs(common.FromHex("0xb39bbc0b3673c7d36450bc14cfcdad2d559c6c640902"))
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1000000000), args.MinimumCKBAmount())
+	assert.Equal(t, "100", args.MinimumUDTAmount().String())
+	assert.Equal(t, common.FromHex("0xb39bbc0b3673c7d36450bc14cfcdad2d559c6c640902"), args.Bytes())
+
+	args, err = ParseArgs(common.FromHex("0xb39bbc0b3673c7d36450bc14cfcdad2d559c6c64"))
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0), args.MinimumCKBAmount())
+	assert.Nil(t, args.MinimumUDTAmount())
+
+	_, err = ParseArgs(common.FromHex("0xb39bbc0b"))
+	assert.True(t, errors.Is(err, ErrInvalidArgs))
+}
+
+func TestTopUp(t *testing.T) {
+	scripts := &utils.SystemScripts{
+		SecpSingleSigCell: &utils.SystemScriptCell{
+			CellHash: types.HexToHash(transaction.SECP256K1_BLAKE160_SIGHASH_ALL_TYPE_HASH),
+			OutPoint: &types.OutPoint{TxHash: types.HexToHash("0x01"), Index: 0},
+		},
+		ACPCell: &utils.SystemScriptCell{
+			CellHash: types.HexToHash(transaction.ANYONE_CAN_PAY_TYPE_HASH_TESTNET),
+			OutPoint: &types.OutPoint{TxHash: types.HexToHash("0x02"), Index: 0},
+		},
+	}
+	payer := &types.Script{
+		CodeHash: types.HexToHash(transaction.SECP256K1_BLAKE160_SIGHASH_ALL_TYPE_HASH),
+		HashType: types.HashTypeType,
+		Args:     common.FromHex("0xedcda9513fa030ce4308e29245a22c022d0443bb"),
+	}
+
+	_, err := NewTopUp(scripts, testACPCell("0xb39bbc0b3673c7d36450bc14cfcdad2d559c6c640a"), []byte{}, 100000000)
+	assert.True(t, errors.Is(err, ErrBelowMinimum))
+
+	_, err = NewTopUp(scripts, &types.Cell{Lock: payer}, []byte{}, 100000000)
+	assert.Equal(t, ErrNotAnyoneCanPayCell, err)
+
+	acpCell := testACPCell("0xb39bbc0b3673c7d36450bc14cfcdad2d559c6c6408")
+	topUp, err := NewTopUp(scripts, acpCell, []byte{}, 100000000)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(topUp.Transaction.CellDeps))
+
+	collector := utils.NewCellCollector(nil, payer, nil)
+	collector.Provider = utils.NewMemoryCellProvider(&types.Cell{
+		Capacity: 20000000000,
+		Lock:     payer,
+		OutPoint: &types.OutPoint{TxHash: types.HexToHash("0x0b"), Index: 0},
+	})
+	tx, err := topUp.Build(collector, payer, 1000)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(tx.Inputs))
+	assert.Equal(t, uint64(14300000000), tx.Outputs[0].Capacity)
+	assert.Equal(t, []byte{}, tx.Witnesses[0])
+	assert.Equal(t, []int{1}, topUp.group)
+
+	assert.Nil(t, transaction.Verify(tx, []*types.CellOutput{
+		{Capacity: 14200000000, Lock: acpCell.Lock},
+		{Capacity: 20000000000, Lock: payer},
+	}))
+}
+
+func TestTopUpTypedCell(t *testing.T) {
+	scripts := &utils.SystemScripts{
+		SecpSingleSigCell: &utils.SystemScriptCell{
+			CellHash: types.HexToHash(transaction.SECP256K1_BLAKE160_SIGHASH_ALL_TYPE_HASH),
+			OutPoint: &types.OutPoint{TxHash: types.HexToHash("0x01"), Index: 0},
+		},
+		ACPCell: &utils.SystemScriptCell{
+			CellHash: types.HexToHash(transaction.ANYONE_CAN_PAY_TYPE_HASH_TESTNET),
+			OutPoint: &types.OutPoint{TxHash: types.HexToHash("0x02"), Index: 0},
+		},
+		SUDTCell: &utils.SystemScriptCell{
+			CellHash: types.HexToHash("0xc5e5dcf215925f7ef4dfaf5f4b4f105bc321c02776d6e7d52a1db3fcd9d011a4"),
+			OutPoint: &types.OutPoint{TxHash: types.HexToHash("0x03"), Index: 0},
+		},
+	}
+	acpCell := testACPCell("0xb39bbc0b3673c7d36450bc14cfcdad2d559c6c64")
+	acpCell.Type = &types.Script{
+		CodeHash: scripts.SUDTCell.CellHash,
+		HashType: types.HashTypeType,
+		Args:     common.FromHex("0x32e555f3ff8e135cece1351a6a2971518392c1e30375c1e006ad0ce8eac07947"),
+	}
+
+	topUp, err := NewTopUp(scripts, acpCell, make([]byte, 16), 100000000)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(topUp.Transaction.CellDeps))
+	assert.Equal(t, &types.CellDep{OutPoint: scripts.SUDTCell.OutPoint, DepType: types.DepTypeCode}, topUp.Transaction.CellDeps[2])
+	assert.Equal(t, acpCell.Type, topUp.Transaction.Outputs[0].Type)
+
+	acpCell.Type = &types.Script{
+		CodeHash: types.HexToHash("0x82d76d1b75fe2fd9a27dfbaa65a039221a380d76c926f378d3f81cf3e7e13f2e"),
+		HashType: types.HashTypeType,
+		Args:     []byte{},
+	}
+	_, err = NewTopUp(scripts, acpCell, []byte{}, 100000000)
+	assert.True(t, errors.Is(err, ErrUnknownTypeScript))
+}
+
+func TestUDTTopUp(t *testing.T) {
+	scripts := &utils.SystemScripts{
+		SecpSingleSigCell: &utils.SystemScriptCell{
+			CellHash: types.HexToHash(transaction.SECP256K1_BLAKE160_SIGHASH_ALL_TYPE_HASH),
+			OutPoint: &types.OutPoint{TxHash: types.HexToHash("0x01"), Index: 0},
+		},
+		ACPCell: &utils.SystemScriptCell{
+			CellHash: types.HexToHash(transaction.ANYONE_CAN_PAY_TYPE_HASH_TESTNET),
+			OutPoint: &types.OutPoint{TxHash: types.HexToHash("0x02"), Index: 0},
+		},
+		SUDTCell: &utils.SystemScriptCell{
+			CellHash: types.HexToHash("0xc5e5dcf215925f7ef4dfaf5f4b4f105bc321c02776d6e7d52a1db3fcd9d011a4"),
+			OutPoint: &types.OutPoint{TxHash: types.HexToHash("0x03"), Index: 0},
+		},
+	}
+	payer := &types.Script{
+		CodeHash: types.HexToHash(transaction.SECP256K1_BLAKE160_SIGHASH_ALL_TYPE_HASH),
+		HashType: types.HashTypeType,
+		Args:     common.FromHex("0xedcda9513fa030ce4308e29245a22c022d0443bb"),
+	}
+	udtType, err := udt.NewTypeScript(scripts, payer)
+	assert.Nil(t, err)
+	udtData := func(amount int64) []byte {
+		data, _ := udt.AmountBytes(big.NewInt(amount))
+		return data
+	}
+
+	// minimum UDT amount is 10^2
+	acpCell := testACPCell("0xb39bbc0b3673c7d36450bc14cfcdad2d559c6c640002")
+	acpCell.Capacity = 14400000000
+	acpCell.Type = udtType
+	acpCell.Data = udtData(1000)
+
+	_, err = NewUDTTopUp(scripts, acpCell, payer, big.NewInt(99))
+	assert.True(t, errors.Is(err, ErrBelowMinimum))
+	_, err = NewUDTTopUp(scripts, testACPCell("0xb39bbc0b3673c7d36450bc14cfcdad2d559c6c64"), payer, big.NewInt(100))
+	assert.Equal(t, ErrNotUDTCell, err)
+
+	topUp, err := NewUDTTopUp(scripts, acpCell, payer, big.NewInt(300))
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(topUp.Transaction.CellDeps))
+	assert.Equal(t, udtData(1300), topUp.Transaction.OutputsData[0])
+
+	collector := utils.NewCellCollector(nil, payer, nil)
+	collector.Provider = utils.NewMemoryCellProvider(
+		&types.Cell{
+			Capacity:      14200000000,
+			Lock:          payer,
+			Type:          udtType,
+			OutPoint:      &types.OutPoint{TxHash: types.HexToHash("0x0b"), Index: 0},
+			OutputDataLen: udt.AmountLength,
+			Data:          udtData(500),
+		},
+		&types.Cell{
+			Capacity: 50000000000,
+			Lock:     payer,
+			OutPoint: &types.OutPoint{TxHash: types.HexToHash("0x0c"), Index: 0},
+		},
+	)
+	_, err = topUp.Build(collector, 1000)
+	assert.Nil(t, err)
+	tx := topUp.Transaction
+	assert.Equal(t, 3, len(tx.Inputs))
+	assert.Equal(t, 3, len(tx.Outputs))
+	assert.Equal(t, acpCell.Capacity, tx.Outputs[0].Capacity)
+	assert.Equal(t, udtData(200), tx.OutputsData[1])
+	assert.Equal(t, []byte{}, tx.Witnesses[0])
+	assert.Equal(t, []int{1, 2}, topUp.group)
+
+	assert.Nil(t, transaction.Verify(tx, []*types.CellOutput{
+		{Capacity: acpCell.Capacity, Lock: acpCell.Lock, Type: udtType},
+		{Capacity: 14200000000, Lock: payer, Type: udtType},
+		{Capacity: 50000000000, Lock: payer},
+	}))
+
+	insufficient, err := NewUDTTopUp(scripts, acpCell, payer, big.NewInt(1000))
+	assert.Nil(t, err)
+	_, err = insufficient.Build(collector, 1000)
+	assert.True(t, errors.Is(err, udt.ErrInsufficientBalance))
+}
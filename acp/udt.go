@@ -0,0 +1,123 @@
+package acp
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ququzone/ckb-sdk-go/crypto"
+	"github.com/ququzone/ckb-sdk-go/transaction"
+	"github.com/ququzone/ckb-sdk-go/types"
+	"github.com/ququzone/ckb-sdk-go/udt"
+	"github.com/ququzone/ckb-sdk-go/utils"
+)
+
+// UDTTopUp transfers simple UDT into an existing anyone-can-pay cell of the same UDT, the amount is paid by
+// UDT cells of payer and capacity of fee and UDT change by plain cells of payer.
+type UDTTopUp struct {
+	Transaction *types.Transaction
+	Payer       *types.Script
+	cell        *types.Cell
+	amount      *big.Int
+	group       []int
+	witnessArgs *types.WitnessArgs
+}
+
+// NewUDTTopUp spends cell and recreates it with amount more UDT, the current amount is read from cell.Data
+// so the cell must be collected with its data. Capacity and data after the amount are kept unchanged.
+func NewUDTTopUp(scripts *utils.SystemScripts, cell *types.Cell, payer *types.Script, amount *big.Int) (*UDTTopUp, error) {
+	if scripts.ACPCell == nil {
+		return nil, ErrUnknownNetwork
+	}
+	if scripts.SUDTCell == nil {
+		return nil, udt.ErrUnknownNetwork
+	}
+	if cell.Lock == nil || cell.Lock.CodeHash != scripts.ACPCell.CellHash || cell.Lock.HashType != types.HashTypeType {
+		return nil, ErrNotAnyoneCanPayCell
+	}
+	if cell.Type == nil || cell.Type.CodeHash != scripts.SUDTCell.CellHash || cell.Type.HashType != types.HashTypeType {
+		return nil, ErrNotUDTCell
+	}
+	if cell.Data == nil {
+		return nil, errors.New("cell data is required")
+	}
+	args, err := ParseArgs(cell.Lock.Args)
+	if err != nil {
+		return nil, err
+	}
+	if amount.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: %s", udt.ErrInvalidAmount, amount.String())
+	}
+	if minimum := args.MinimumUDTAmount(); minimum != nil && amount.Cmp(minimum) < 0 {
+		return nil, fmt.Errorf("%w: %s is less than %s", ErrBelowMinimum, amount.String(), minimum.String())
+	}
+
+	current, err := udt.ParseAmount(cell.Data)
+	if err != nil {
+		return nil, err
+	}
+	amountData, err := udt.AmountBytes(new(big.Int).Add(current, amount))
+	if err != nil {
+		return nil, err
+	}
+
+	dep, err := typeCellDep(scripts, cell.Type)
+	if err != nil {
+		return nil, err
+	}
+	tx := NewAnyoneCanPayTx(scripts)
+	tx.CellDeps = append(tx.CellDeps, dep)
+	tx.Inputs = append(tx.Inputs, &types.CellInput{
+		Since: 0,
+		PreviousOutput: &types.OutPoint{
+			TxHash: cell.OutPoint.TxHash,
+			Index:  cell.OutPoint.Index,
+		},
+	})
+	// receiver does not sign, UDT amount only increases
+	tx.Witnesses = append(tx.Witnesses, []byte{})
+	tx.Outputs = append(tx.Outputs, &types.CellOutput{
+		Capacity: cell.Capacity,
+		Lock:     cell.Lock,
+		Type:     cell.Type,
+	})
+	tx.OutputsData = append(tx.OutputsData, append(amountData, cell.Data[udt.AmountLength:]...))
+
+	return &UDTTopUp{
+		Transaction: tx,
+		Payer:       payer,
+		cell:        cell,
+		amount:      amount,
+	}, nil
+}
+
+// Build collects UDT cells and plain cells of payer by collector, collector lock must be payer lock
+func (t *UDTTopUp) Build(collector *utils.CellCollector, feeRate uint64) (*types.Transaction, error) {
+	if !t.Payer.Equals(collector.LockScript) {
+		return nil, errors.New("collector lock is not payer")
+	}
+	if len(t.group) > 0 {
+		return nil, errors.New("transaction already built")
+	}
+
+	tx, group, witnessArgs, err := udt.CompleteTransfer(t.Transaction, collector, t.cell.Type, t.amount, t.cell.Capacity, feeRate)
+	if err != nil {
+		return nil, err
+	}
+
+	*t.Transaction = *tx
+	t.group = group
+	t.witnessArgs = witnessArgs
+	return t.Transaction, nil
+}
+
+func (t *UDTTopUp) Sign(key crypto.Key) (*types.Transaction, error) {
+	if len(t.group) == 0 {
+		return nil, errors.New("must build transaction first")
+	}
+	err := transaction.SingleSignTransaction(t.Transaction, t.group, t.witnessArgs, key)
+	if err != nil {
+		return nil, fmt.Errorf("sign transaction error: %v", err)
+	}
+	return t.Transaction, nil
+}
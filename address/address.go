@@ -3,7 +3,7 @@ package address
 import (
 	"encoding/hex"
 	"errors"
-	"strings"
+	"fmt"
 
 	"github.com/ethereum/go-ethereum/common"
 
@@ -22,13 +22,43 @@ const (
 	TypeFull  Type = "Full"
 	TypeShort Type = "Short"
 
-	SHORT_FORMAT                 = "01"
-	FULL_DATA_FORMAT             = "02"
-	FULL_TYPE_FORMAT             = "04"
-	CODE_HASH_INDEX_SINGLESIG    = "00"
-	CODE_HASH_INDEX_MULTISIG_SIG = "01"
+	FULL_FORMAT                    = "00"
+	SHORT_FORMAT                   = "01"
+	FULL_DATA_FORMAT               = "02"
+	FULL_TYPE_FORMAT               = "04"
+	CODE_HASH_INDEX_SINGLESIG      = "00"
+	CODE_HASH_INDEX_MULTISIG_SIG   = "01"
+	CODE_HASH_INDEX_ANYONE_CAN_PAY = "02"
 )
 
+// shortArgsLength is the blake160 args length of short format locks
+const shortArgsLength = 20
+
+var (
+	ErrInvalidEncoding      = errors.New("invalid address encoding")
+	ErrUnknownNetwork       = errors.New("unknown address network")
+	ErrEncodingMismatch     = errors.New("address encoding does not match payload format")
+	ErrUnknownFormat        = errors.New("unknown address format")
+	ErrInvalidPayloadLength = errors.New("invalid address payload length")
+	ErrUnknownCodeHashIndex = errors.New("unknown short address code hash index")
+	ErrInvalidArgsLength    = errors.New("invalid short address args length")
+	ErrUnknownHashType      = errors.New("unknown address hash type")
+)
+
+// Format is the payload format Generate prefers
+type Format int
+
+const (
+	// FormatLegacy emits short payload for secp256k1 locks and full data or full type payload for other scripts,
+	// encoded with bech32. Scripts of hash type data1 are always emitted in FormatFull.
+	FormatLegacy Format = iota
+	// FormatFull emits full payload with hash type byte, encoded with bech32m
+	FormatFull
+)
+
+// DefaultFormat is the format used by Generate
+var DefaultFormat = FormatLegacy
+
 type ParsedAddress struct {
 	Mode   Mode
 	Type   Type
@@ -36,6 +66,14 @@ type ParsedAddress struct {
 }
 
 func Generate(mode Mode, script *types.Script) (string, error) {
+	return GenerateWithFormat(mode, script, DefaultFormat)
+}
+
+func GenerateWithFormat(mode Mode, script *types.Script, format Format) (string, error) {
+	if format == FormatFull || script.HashType == types.HashTypeData1 {
+		return generateFullFormatAddress(mode, script)
+	}
+
 	if script.HashType == types.HashTypeType && len(script.Args) == 20 {
 		if transaction.SECP256K1_BLAKE160_SIGHASH_ALL_TYPE_HASH == script.CodeHash.String() {
 			// generate_short_payload_singlesig_address
@@ -56,6 +94,18 @@ func Generate(mode Mode, script *types.Script) (string, error) {
 		}
 	}
 
+	// anyone-can-pay args may carry minimum amount exponents after blake160
+	if script.HashType == types.HashTypeType && len(script.Args) >= shortArgsLength && len(script.Args) <= shortArgsLength+2 &&
+		anyoneCanPayCodeHash(mode) == script.CodeHash.String() {
+		// generate_short_payload_acp_address
+		payload := SHORT_FORMAT + CODE_HASH_INDEX_ANYONE_CAN_PAY + hex.EncodeToString(script.Args)
+		data, err := bech32.ConvertBits(common.FromHex(payload), 8, 5, true)
+		if err != nil {
+			return "", err
+		}
+		return bech32.Encode((string)(mode), data)
+	}
+
 	hashType := FULL_TYPE_FORMAT
 	if script.HashType == types.HashTypeData {
 		hashType = FULL_DATA_FORMAT
@@ -64,6 +114,19 @@ func Generate(mode Mode, script *types.Script) (string, error) {
 	return generateFullPayloadAddress(hashType, mode, script)
 }
 
+func generateFullFormatAddress(mode Mode, script *types.Script) (string, error) {
+	hashType, err := script.HashType.Serialize()
+	if err != nil {
+		return "", err
+	}
+	payload := FULL_FORMAT + hex.EncodeToString(script.CodeHash.Bytes()) + hex.EncodeToString(hashType) + hex.EncodeToString(script.Args)
+	data, err := bech32.ConvertBits(common.FromHex(payload), 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	return bech32.EncodeWithEncoding((string)(mode), data, bech32.BECH32M)
+}
+
 func generateFullPayloadAddress(hashType string, mode Mode, script *types.Script) (string, error) {
 	payload := hashType + hex.EncodeToString(script.CodeHash.Bytes()) + hex.EncodeToString(script.Args)
 	data, err := bech32.ConvertBits(common.FromHex(payload), 8, 5, true)
@@ -73,56 +136,100 @@ func generateFullPayloadAddress(hashType string, mode Mode, script *types.Script
 	return bech32.Encode((string)(mode), data)
 }
 
+// Parse parses address, errors returned wrap one of the ErrXxx values
 func Parse(address string) (*ParsedAddress, error) {
-	hrp, decoded, err := bech32.Decode(address)
+	encoding, hrp, decoded, err := bech32.DecodeWithEncoding(address)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEncoding, err)
+	}
+	mode := Mode(hrp)
+	if mode != Mainnet && mode != Testnet {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownNetwork, hrp)
 	}
-	data, err := bech32.ConvertBits(decoded, 5, 8, false)
+	payload, err := bech32.ConvertBits(decoded, 5, 8, false)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEncoding, err)
+	}
+	if len(payload) == 0 {
+		return nil, fmt.Errorf("%w: payload is empty", ErrInvalidPayloadLength)
+	}
+
+	format := hex.EncodeToString(payload[:1])
+	// full format must be bech32m while legacy formats must be bech32
+	if (format == FULL_FORMAT) != (encoding == bech32.BECH32M) {
+		return nil, fmt.Errorf("%w: format %s", ErrEncodingMismatch, format)
 	}
-	payload := hex.EncodeToString(data)
 
 	var addressType Type
 	var script types.Script
-	if strings.HasPrefix(payload, "01") {
-		addressType = TypeShort
-		if CODE_HASH_INDEX_SINGLESIG == payload[2:4] {
-			script = types.Script{
-				CodeHash: types.HexToHash(transaction.SECP256K1_BLAKE160_SIGHASH_ALL_TYPE_HASH),
-				HashType: types.HashTypeType,
-				Args:     common.Hex2Bytes(payload[4:]),
-			}
-		} else {
-			script = types.Script{
-				CodeHash: types.HexToHash(transaction.SECP256K1_BLAKE160_MULTISIG_ALL_TYPE_HASH),
-				HashType: types.HashTypeType,
-				Args:     common.Hex2Bytes(payload[4:]),
-			}
+	switch format {
+	case FULL_FORMAT:
+		if len(payload) < 1+types.HashLength+1 {
+			return nil, fmt.Errorf("%w: full format payload of %d bytes", ErrInvalidPayloadLength, len(payload))
 		}
-	} else if strings.HasPrefix(payload, "02") {
 		addressType = TypeFull
 		script = types.Script{
-			CodeHash: types.HexToHash(payload[2:66]),
-			HashType: types.HashTypeData,
-			Args:     common.Hex2Bytes(payload[66:]),
+			CodeHash: types.BytesToHash(payload[1 : 1+types.HashLength]),
+			Args:     payload[2+types.HashLength:],
+		}
+		if err := script.HashType.Deserialize(payload[1+types.HashLength : 2+types.HashLength]); err != nil {
+			return nil, fmt.Errorf("%w: %d", ErrUnknownHashType, payload[1+types.HashLength])
+		}
+	case SHORT_FORMAT:
+		if len(payload) < 2 {
+			return nil, fmt.Errorf("%w: short format payload of %d bytes", ErrInvalidPayloadLength, len(payload))
+		}
+		addressType = TypeShort
+		var codeHash string
+		maxArgsLength := shortArgsLength
+		switch hex.EncodeToString(payload[1:2]) {
+		case CODE_HASH_INDEX_SINGLESIG:
+			codeHash = transaction.SECP256K1_BLAKE160_SIGHASH_ALL_TYPE_HASH
+		case CODE_HASH_INDEX_MULTISIG_SIG:
+			codeHash = transaction.SECP256K1_BLAKE160_MULTISIG_ALL_TYPE_HASH
+		case CODE_HASH_INDEX_ANYONE_CAN_PAY:
+			codeHash = anyoneCanPayCodeHash(mode)
+			maxArgsLength = shortArgsLength + 2
+		default:
+			return nil, fmt.Errorf("%w: %d", ErrUnknownCodeHashIndex, payload[1])
+		}
+		if len(payload) < 2+shortArgsLength || len(payload) > 2+maxArgsLength {
+			return nil, fmt.Errorf("%w: short format args of %d bytes", ErrInvalidArgsLength, len(payload)-2)
+		}
+		script = types.Script{
+			CodeHash: types.HexToHash(codeHash),
+			HashType: types.HashTypeType,
+			Args:     payload[2:],
+		}
+	case FULL_DATA_FORMAT, FULL_TYPE_FORMAT:
+		if len(payload) < 1+types.HashLength {
+			return nil, fmt.Errorf("%w: full format payload of %d bytes", ErrInvalidPayloadLength, len(payload))
 		}
-	} else if strings.HasPrefix(payload, "04") {
 		addressType = TypeFull
 		script = types.Script{
-			CodeHash: types.HexToHash(payload[2:66]),
+			CodeHash: types.BytesToHash(payload[1 : 1+types.HashLength]),
 			HashType: types.HashTypeType,
-			Args:     common.Hex2Bytes(payload[66:]),
+			Args:     payload[1+types.HashLength:],
+		}
+		if format == FULL_DATA_FORMAT {
+			script.HashType = types.HashTypeData
 		}
-	} else {
-		return nil, errors.New("address type error:" + payload[:2])
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownFormat, format)
 	}
 
 	result := &ParsedAddress{
-		Mode:   Mode(hrp),
+		Mode:   mode,
 		Type:   addressType,
 		Script: &script,
 	}
 	return result, nil
 }
+
+// anyoneCanPayCodeHash returns the anyone-can-pay lock type hash deployed on network of mode
+func anyoneCanPayCodeHash(mode Mode) string {
+	if mode == Mainnet {
+		return transaction.ANYONE_CAN_PAY_TYPE_HASH_MAINNET
+	}
+	return transaction.ANYONE_CAN_PAY_TYPE_HASH_TESTNET
+}
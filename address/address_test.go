@@ -1,11 +1,14 @@
 package address
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/ququzone/ckb-sdk-go/crypto/bech32"
+	"github.com/ququzone/ckb-sdk-go/transaction"
 	"github.com/ququzone/ckb-sdk-go/types"
 )
 
@@ -42,3 +45,122 @@ func TestParse(t *testing.T) {
 	assert.Equal(t, script.HashType, mnAddress.Script.HashType)
 	assert.Equal(t, script.Args, mnAddress.Script.Args)
 }
+
+func TestAnyoneCanPay(t *testing.T) {
+	for _, args := range []string{
+		"0xb39bbc0b3673c7d36450bc14cfcdad2d559c6c64",
+		"0xb39bbc0b3673c7d36450bc14cfcdad2d559c6c6409",
+		"0xb39bbc0b3673c7d36450bc14cfcdad2d559c6c640902",
+	} {
+		script := &types.Script{
+			CodeHash: types.HexToHash(transaction.ANYONE_CAN_PAY_TYPE_HASH_TESTNET),
+			HashType: types.HashTypeType,
+			Args:     common.FromHex(args),
+		}
+		address, err := Generate(Testnet, script)
+		assert.Nil(t, err)
+
+		parsed, err := Parse(address)
+		assert.Nil(t, err)
+		assert.Equal(t, TypeShort, parsed.Type)
+		assert.True(t, script.Equals(parsed.Script))
+	}
+
+	// testnet code hash is not anyone-can-pay lock on mainnet
+	address, err := Generate(Mainnet, &types.Script{
+		CodeHash: types.HexToHash(transaction.ANYONE_CAN_PAY_TYPE_HASH_TESTNET),
+		HashType: types.HashTypeType,
+		Args:     common.FromHex("0xb39bbc0b3673c7d36450bc14cfcdad2d559c6c64"),
+	})
+	assert.Nil(t, err)
+	parsed, err := Parse(address)
+	assert.Nil(t, err)
+	assert.Equal(t, TypeFull, parsed.Type)
+}
+
+func TestFullFormat(t *testing.T) {
+	// vectors of RFC 0021
+	script := &types.Script{
+		CodeHash: types.HexToHash("0x9bd7e06f3ecf4be0f2fcd2188b23f1b9fcc88e5d4b65a8637b17723bbda3cce8"),
+		HashType: types.HashTypeType,
+		Args:     common.FromHex("0xb39bbc0b3673c7d36450bc14cfcdad2d559c6c64"),
+	}
+	vectors := map[Format]string{
+		FormatLegacy: "ckb1qyqt8xaupvm8837nv3gtc9x0ekkj64vud3jqfwyw5v",
+		FormatFull:   "ckb1qzda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xwsqdnnw7qkdnnclfkg59uzn8umtfd2kwxceqxwquc4",
+	}
+	for format, vector := range vectors {
+		address, err := GenerateWithFormat(Mainnet, script, format)
+		assert.Nil(t, err)
+		assert.Equal(t, vector, address)
+
+		parsed, err := Parse(vector)
+		assert.Nil(t, err)
+		assert.Equal(t, Mainnet, parsed.Mode)
+		assert.True(t, script.Equals(parsed.Script))
+	}
+
+	parsed, err := Parse("ckb1qjda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xw3vumhs9nvu786dj9p0q5elx66t24n3kxgj53qks")
+	assert.Nil(t, err)
+	assert.Equal(t, TypeFull, parsed.Type)
+	assert.True(t, script.Equals(parsed.Script))
+
+	script.HashType = types.HashTypeData1
+	data1 := "ckb1qzda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xwsq4nnw7qkdnnclfkg59uzn8umtfd2kwxceqcydzyt"
+	address, err := Generate(Mainnet, script)
+	assert.Nil(t, err)
+	assert.Equal(t, data1, address)
+	parsed, err = Parse(data1)
+	assert.Nil(t, err)
+	assert.Equal(t, TypeFull, parsed.Type)
+	assert.Equal(t, types.HashTypeData1, parsed.Script.HashType)
+	assert.Equal(t, script.Args, parsed.Script.Args)
+}
+
+func TestParseEncodingMismatch(t *testing.T) {
+	script := &types.Script{
+		CodeHash: types.HexToHash("0x9bd7e06f3ecf4be0f2fcd2188b23f1b9fcc88e5d4b65a8637b17723bbda3cce8"),
+		HashType: types.HashTypeType,
+		Args:     common.FromHex("0xb39bbc0b3673c7d36450bc14cfcdad2d559c6c64"),
+	}
+	hashType, _ := script.HashType.Serialize()
+	payload := append(append(append([]byte{0x00}, script.CodeHash.Bytes()...), hashType...), script.Args...)
+	data, err := bech32.ConvertBits(payload, 8, 5, true)
+	assert.Nil(t, err)
+	// full format encoded with bech32 is invalid
+	address, err := bech32.Encode(string(Mainnet), data)
+	assert.Nil(t, err)
+	_, err = Parse(address)
+	assert.True(t, errors.Is(err, ErrEncodingMismatch))
+}
+
+func encodeTestPayload(t *testing.T, hrp string, payload []byte, encoding bech32.Encoding) string {
+	data, err := bech32.ConvertBits(payload, 8, 5, true)
+	assert.Nil(t, err)
+	address, err := bech32.EncodeWithEncoding(hrp, data, encoding)
+	assert.Nil(t, err)
+	return address
+}
+
+func TestParseErrors(t *testing.T) {
+	args := common.FromHex("0xb39bbc0b3673c7d36450bc14cfcdad2d559c6c64")
+	codeHash := common.FromHex("0x9bd7e06f3ecf4be0f2fcd2188b23f1b9fcc88e5d4b65a8637b17723bbda3cce8")
+
+	cases := map[string]error{
+		"ckb1qyqt8xaupvm8837nv3gtc9x0ekkj64vud3jqfwyw5x":                                          ErrInvalidEncoding,
+		encodeTestPayload(t, "bc", append([]byte{0x01, 0x00}, args...), bech32.BECH32):            ErrUnknownNetwork,
+		encodeTestPayload(t, "ckb", []byte{}, bech32.BECH32):                                      ErrInvalidPayloadLength,
+		encodeTestPayload(t, "ckb", []byte{0x01}, bech32.BECH32):                                  ErrInvalidPayloadLength,
+		encodeTestPayload(t, "ckb", append([]byte{0x01, 0x05}, args...), bech32.BECH32):           ErrUnknownCodeHashIndex,
+		encodeTestPayload(t, "ckb", append([]byte{0x01, 0x00}, args[:19]...), bech32.BECH32):      ErrInvalidArgsLength,
+		encodeTestPayload(t, "ckb", append([]byte{0x04}, codeHash[:31]...), bech32.BECH32):        ErrInvalidPayloadLength,
+		encodeTestPayload(t, "ckb", append([]byte{0x00}, codeHash...), bech32.BECH32M):            ErrInvalidPayloadLength,
+		encodeTestPayload(t, "ckb", append(append([]byte{0x00}, codeHash...), 9), bech32.BECH32M): ErrUnknownHashType,
+		encodeTestPayload(t, "ckb", append([]byte{0x01, 0x00}, args...), bech32.BECH32M):          ErrEncodingMismatch,
+		encodeTestPayload(t, "ckb", append([]byte{0x03}, codeHash...), bech32.BECH32):             ErrUnknownFormat,
+	}
+	for address, want := range cases {
+		_, err := Parse(address)
+		assert.True(t, errors.Is(err, want), "%s: %v", address, err)
+	}
+}
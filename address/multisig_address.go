@@ -3,12 +3,58 @@ package address
 import (
 	"encoding/binary"
 	"errors"
+	"fmt"
 
 	"github.com/ququzone/ckb-sdk-go/crypto/blake2b"
 	"github.com/ququzone/ckb-sdk-go/transaction"
 	"github.com/ququzone/ckb-sdk-go/types"
 )
 
+// multisigSinceArgsLength is the length of multisig args with since, blake160 of multisig script followed by since
+const multisigSinceArgsLength = 28
+
+var ErrInvalidMultisigArgs = errors.New("invalid multisig args")
+
+// MultisigArgs are args of secp256k1 multisig lock, inputs of a lock with Since must set since not less than it
+type MultisigArgs struct {
+	Hash  []byte
+	Since *types.Since
+}
+
+func ParseMultisigArgs(args []byte) (*MultisigArgs, error) {
+	switch len(args) {
+	case shortArgsLength:
+		return &MultisigArgs{Hash: args}, nil
+	case multisigSinceArgsLength:
+		since, err := types.ParseSince(binary.LittleEndian.Uint64(args[shortArgsLength:]))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidMultisigArgs, err)
+		}
+		return &MultisigArgs{Hash: args[:shortArgsLength], Since: &since}, nil
+	default:
+		return nil, fmt.Errorf("%w: length %d", ErrInvalidMultisigArgs, len(args))
+	}
+}
+
+func (a *MultisigArgs) Bytes() []byte {
+	args := append([]byte{}, a.Hash...)
+	if a.Since != nil {
+		args = append(args, types.SerializeUint64(a.Since.Uint64())...)
+	}
+	return args
+}
+
+// GenerateSecp256k1MultisigScriptWithSince returns multisig lock whose cells can only be spent since since,
+// its address is in full format as the args are 28 bytes.
+func GenerateSecp256k1MultisigScriptWithSince(requireN, threshold int, publicKeys [][]byte, since types.Since) (*types.Script, []byte, error) {
+	script, serialize, err := GenerateSecp256k1MultisigScript(requireN, threshold, publicKeys)
+	if err != nil {
+		return nil, nil, err
+	}
+	script.Args = (&MultisigArgs{Hash: script.Args, Since: &since}).Bytes()
+	return script, serialize, nil
+}
+
 func GenerateSecp256k1MultisigScript(requireN, threshold int, publicKeys [][]byte) (*types.Script, []byte, error) {
 	if requireN < 0 || requireN > 255 {
 		return nil, nil, errors.New("requireN must ranging from 0 to 255")
@@ -2,9 +2,12 @@ package address
 
 import (
 	"encoding/hex"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/ququzone/ckb-sdk-go/types"
 )
 
 func TestGenerateSecp256k1MultisigScript(t *testing.T) {
@@ -39,3 +42,40 @@ func TestGenerateSecp256k1MultisigScript(t *testing.T) {
 	}
 	assert.Equal(t, "ckt1qyqlqn8vsj7r0a5rvya76tey9jd2rdnca8lqh4kcuq", address)
 }
+
+func TestGenerateSecp256k1MultisigScriptWithSince(t *testing.T) {
+	var publicKeys [][]byte
+	for _, pub := range []string{
+		"032edb83018b57ddeb9bcc7287c5cc5da57e6e0289d31c9e98cb361e88678d6288",
+		"033aeb3fdbfaac72e9e34c55884a401ee87115302c146dd9e314677d826375dc8f",
+		"029a685b8206550ea1b600e347f18fd6115bffe582089d3567bec7eba57d04df01",
+	} {
+		key, err := hex.DecodeString(pub)
+		assert.Nil(t, err)
+		publicKeys = append(publicKeys, key)
+	}
+
+	since, err := types.NewAbsoluteEpochSince(&types.EpochParams{Number: 1000, Index: 0, Length: 1})
+	assert.Nil(t, err)
+	script, _, err := GenerateSecp256k1MultisigScriptWithSince(0, 2, publicKeys, since)
+	assert.Nil(t, err)
+	assert.Equal(t, 28, len(script.Args))
+	assert.Equal(t, "f04cec84bc37f683613bed2f242c9aa1b678e9fe", hex.EncodeToString(script.Args[:20]))
+
+	args, err := ParseMultisigArgs(script.Args)
+	assert.Nil(t, err)
+	assert.Equal(t, since, *args.Since)
+	assert.Equal(t, script.Args, args.Bytes())
+
+	address, err := Generate(Testnet, script)
+	assert.Nil(t, err)
+	parsed, err := Parse(address)
+	assert.Nil(t, err)
+	assert.Equal(t, TypeFull, parsed.Type)
+	assert.True(t, script.Equals(parsed.Script))
+
+	_, err = ParseMultisigArgs(script.Args[:24])
+	assert.True(t, errors.Is(err, ErrInvalidMultisigArgs))
+	_, err = ParseMultisigArgs(append(script.Args[:20:20], 0, 0, 0, 0, 0, 0, 0, 0x01))
+	assert.True(t, errors.Is(err, ErrInvalidMultisigArgs))
+}
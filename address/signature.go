@@ -0,0 +1,35 @@
+package address
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ququzone/ckb-sdk-go/crypto/secp256k1"
+	"github.com/ququzone/ckb-sdk-go/transaction"
+	"github.com/ququzone/ckb-sdk-go/types"
+)
+
+var ErrNotSingleKeyLock = errors.New("address lock is not owned by a single secp256k1 key")
+
+// VerifySignature reports whether signature of digest is made by the owner key of address, the lock of address
+// must be secp256k1 single sign or anyone-can-pay. Use secp256k1.HashMessage for digest of a personal message.
+func VerifySignature(address string, digest []byte, signature []byte) (bool, error) {
+	parsed, err := Parse(address)
+	if err != nil {
+		return false, err
+	}
+	script := parsed.Script
+	if script.HashType != types.HashTypeType || len(script.Args) < shortArgsLength {
+		return false, fmt.Errorf("%w: %s", ErrNotSingleKeyLock, address)
+	}
+	switch script.CodeHash {
+	case types.HexToHash(transaction.SECP256K1_BLAKE160_SIGHASH_ALL_TYPE_HASH):
+		if len(script.Args) != shortArgsLength {
+			return false, fmt.Errorf("%w: %s", ErrNotSingleKeyLock, address)
+		}
+	case types.HexToHash(anyoneCanPayCodeHash(parsed.Mode)):
+	default:
+		return false, fmt.Errorf("%w: %s", ErrNotSingleKeyLock, address)
+	}
+	return secp256k1.VerifyLockArgs(script.Args[:shortArgsLength], digest, signature), nil
+}
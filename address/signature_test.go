@@ -0,0 +1,64 @@
+package address
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ququzone/ckb-sdk-go/crypto/blake2b"
+	"github.com/ququzone/ckb-sdk-go/crypto/secp256k1"
+	"github.com/ququzone/ckb-sdk-go/transaction"
+	"github.com/ququzone/ckb-sdk-go/types"
+)
+
+func TestVerifySignature(t *testing.T) {
+	key, err := secp256k1.HexToKey("e79f3207ea4980b7fed79956d5934249ceac4751a4fae01a0f7c4a96884bc4e3")
+	assert.NoError(t, err)
+	script := singleSignScript(t, key)
+	digest, _ := secp256k1.HashMessage([]byte("login challenge"))
+	signature, err := key.Sign(digest)
+	assert.NoError(t, err)
+
+	addr, err := Generate(Testnet, script)
+	assert.NoError(t, err)
+	ok, err := VerifySignature(addr, digest, signature)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	other, _ := secp256k1.RandomNew()
+	otherAddress, _ := Generate(Testnet, singleSignScript(t, other))
+	ok, err = VerifySignature(otherAddress, digest, signature)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	acp := &types.Script{
+		CodeHash: types.HexToHash(transaction.ANYONE_CAN_PAY_TYPE_HASH_TESTNET),
+		HashType: types.HashTypeType,
+		Args:     append(append([]byte{}, script.Args...), 1),
+	}
+	acpAddress, err := Generate(Testnet, acp)
+	assert.NoError(t, err)
+	ok, err = VerifySignature(acpAddress, digest, signature)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	multisig := &types.Script{
+		CodeHash: types.HexToHash(transaction.SECP256K1_BLAKE160_MULTISIG_ALL_TYPE_HASH),
+		HashType: types.HashTypeType,
+		Args:     script.Args,
+	}
+	multisigAddress, _ := Generate(Testnet, multisig)
+	_, err = VerifySignature(multisigAddress, digest, signature)
+	assert.True(t, errors.Is(err, ErrNotSingleKeyLock))
+}
+
+func singleSignScript(t *testing.T, key *secp256k1.Secp256k1Key) *types.Script {
+	args, err := blake2b.Blake160(key.PubKey())
+	assert.NoError(t, err)
+	return &types.Script{
+		CodeHash: types.HexToHash(transaction.SECP256K1_BLAKE160_SIGHASH_ALL_TYPE_HASH),
+		HashType: types.HashTypeType,
+		Args:     args,
+	}
+}
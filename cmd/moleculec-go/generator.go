@@ -0,0 +1,308 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strings"
+)
+
+const typesImport = "github.com/ququzone/ckb-sdk-go/types"
+
+// generate returns formatted go source for the declarations of s which are not imported.
+func generate(s *schema, pkg string, source string) ([]byte, error) {
+	g := &generator{schema: s}
+
+	g.printf("// Code generated by moleculec-go. DO NOT EDIT.\n")
+	if source != "" {
+		g.printf("// Source: %s\n", source)
+	}
+	g.printf("\npackage %s\n\n", pkg)
+	g.printf("import (\n\t%q\n)\n", typesImport)
+
+	for _, d := range s.Decls {
+		if d.Imported {
+			continue
+		}
+		g.printf("\n")
+		switch d.Kind {
+		case kindArray:
+			g.genArray(d)
+		case kindStruct:
+			g.genStruct(d)
+		case kindFixVec, kindDynVec:
+			g.genVector(d)
+		case kindTable:
+			g.genTable(d)
+		case kindOption:
+			g.genOption(d)
+		case kindUnion:
+			g.genUnion(d)
+		}
+	}
+
+	src, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code error: %v", err)
+	}
+	return src, nil
+}
+
+type generator struct {
+	schema *schema
+	buf    bytes.Buffer
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// goType returns the go type name of a molecule type
+func (g *generator) goType(typ string) string {
+	if typ == primitiveByte {
+		return "byte"
+	}
+	return exportName(typ)
+}
+
+// encode writes statements which serialize expr into target, a serialize error returns from the method
+func (g *generator) encode(typ, expr, target string) {
+	if typ == primitiveByte {
+		g.printf("%s = []byte{%s}\n", target, expr)
+		return
+	}
+	g.printf("%s, err = %s.Serialize()\n", target, expr)
+	g.printf("if err != nil {\nreturn nil, err\n}\n")
+}
+
+// decode writes statements which deserialize source into the addressable target of molecule type name,
+// a byte is checked to be 1 byte as table fields are not sized by the caller
+func (g *generator) decode(name, typ, source, target string) {
+	if typ == primitiveByte {
+		g.printf("if len(%s) != 1 {\nreturn %s\n}\n", source, g.verificationError(name, "ErrTotalSizeNotMatch"))
+		g.printf("%s = %s[0]\n", target, source)
+		return
+	}
+	g.printf("if err := %s.Deserialize(%s); err != nil {\nreturn err\n}\n", target, source)
+}
+
+func (g *generator) verificationError(name, err string) string {
+	return fmt.Sprintf("&types.VerificationError{Name: %q, Err: types.%s}", name, err)
+}
+
+func (g *generator) size(typ string) int {
+	size, _ := g.schema.size(typ)
+	return size
+}
+
+func (g *generator) genArray(d *decl) {
+	name := exportName(d.Name)
+	item := g.goType(d.Item)
+
+	g.printf("type %s [%d]%s\n\n", name, d.Length, item)
+
+	if d.Item == primitiveByte {
+		g.printf("func (v *%s) Serialize() ([]byte, error) {\nreturn v[:], nil\n}\n\n", name)
+		g.printf("func (v *%s) Deserialize(data []byte) error {\n", name)
+		g.printf("if len(data) != %d {\nreturn %s\n}\n", d.Length, g.verificationError(d.Name, "ErrTotalSizeNotMatch"))
+		g.printf("copy(v[:], data)\nreturn nil\n}\n")
+		return
+	}
+
+	g.printf("func (v *%s) Serialize() ([]byte, error) {\n", name)
+	g.printf("fields := make([][]byte, %d)\n", d.Length)
+	g.printf("var err error\n")
+	g.printf("for i := 0; i < %d; i++ {\n", d.Length)
+	g.encode(d.Item, "v[i]", "fields[i]")
+	g.printf("}\nreturn types.SerializeStruct(fields), nil\n}\n\n")
+
+	g.printf("func (v *%s) Deserialize(data []byte) error {\n", name)
+	g.printf("sizes := make([]uint, %d)\n", d.Length)
+	g.printf("for i := range sizes {\nsizes[i] = %d\n}\n", g.size(d.Item))
+	g.printf("fields, err := types.DeserializeStruct(data, sizes)\n")
+	g.printf("if err != nil {\nreturn err\n}\n")
+	g.printf("for i := 0; i < %d; i++ {\n", d.Length)
+	g.decode(d.Name, d.Item, "fields[i]", "v[i]")
+	g.printf("}\nreturn nil\n}\n")
+}
+
+func (g *generator) genFields(d *decl) {
+	g.printf("type %s struct {\n", exportName(d.Name))
+	for _, f := range d.Fields {
+		g.printf("%s %s\n", exportName(f.Name), g.goType(f.Type))
+	}
+	g.printf("}\n\n")
+}
+
+func (g *generator) serializeFields(d *decl) {
+	g.printf("fields := make([][]byte, %d)\n", len(d.Fields))
+	for _, f := range d.Fields {
+		if f.Type != primitiveByte {
+			g.printf("var err error\n")
+			break
+		}
+	}
+	for i, f := range d.Fields {
+		g.encode(f.Type, "v."+exportName(f.Name), fmt.Sprintf("fields[%d]", i))
+	}
+}
+
+func (g *generator) deserializeFields(d *decl) {
+	for i, f := range d.Fields {
+		g.decode(d.Name, f.Type, fmt.Sprintf("fields[%d]", i), "v."+exportName(f.Name))
+	}
+	g.printf("return nil\n}\n")
+}
+
+func (g *generator) genStruct(d *decl) {
+	name := exportName(d.Name)
+	g.genFields(d)
+
+	g.printf("func (v *%s) Serialize() ([]byte, error) {\n", name)
+	g.serializeFields(d)
+	g.printf("return types.SerializeStruct(fields), nil\n}\n\n")
+
+	sizes := make([]string, len(d.Fields))
+	for i, f := range d.Fields {
+		sizes[i] = fmt.Sprint(g.size(f.Type))
+	}
+	g.printf("func (v *%s) Deserialize(data []byte) error {\n", name)
+	g.printf("fields, err := types.DeserializeStruct(data, []uint{%s})\n", strings.Join(sizes, ", "))
+	g.printf("if err != nil {\nreturn err\n}\n")
+	g.deserializeFields(d)
+}
+
+func (g *generator) genTable(d *decl) {
+	name := exportName(d.Name)
+	g.genFields(d)
+
+	g.printf("func (v *%s) Serialize() ([]byte, error) {\n", name)
+	g.serializeFields(d)
+	g.printf("return types.SerializeTable(fields), nil\n}\n\n")
+
+	g.printf("func (v *%s) Deserialize(data []byte) error {\n", name)
+	if len(d.Fields) == 0 {
+		g.printf("_, err := types.DeserializeTable(data, 0)\nreturn err\n}\n")
+		return
+	}
+	g.printf("fields, err := types.DeserializeTable(data, %d)\n", len(d.Fields))
+	g.printf("if err != nil {\nreturn err\n}\n")
+	g.deserializeFields(d)
+}
+
+func (g *generator) genVector(d *decl) {
+	name := exportName(d.Name)
+	item := g.goType(d.Item)
+
+	g.printf("type %s []%s\n\n", name, item)
+
+	if d.Item == primitiveByte {
+		g.printf("func (v *%s) Serialize() ([]byte, error) {\nreturn types.SerializeBytes(*v), nil\n}\n\n", name)
+		g.printf("func (v *%s) Deserialize(data []byte) error {\n", name)
+		g.printf("b, err := types.DeserializeBytes(data)\nif err != nil {\nreturn err\n}\n")
+		g.printf("*v = b\nreturn nil\n}\n")
+		return
+	}
+
+	g.printf("func (v *%s) Serialize() ([]byte, error) {\n", name)
+	g.printf("items := make([][]byte, len(*v))\n")
+	g.printf("var err error\n")
+	g.printf("for i := 0; i < len(*v); i++ {\n")
+	g.encode(d.Item, "(*v)[i]", "items[i]")
+	if d.Kind == kindFixVec {
+		g.printf("}\nreturn types.SerializeFixVec(items), nil\n}\n\n")
+	} else {
+		g.printf("}\nreturn types.SerializeDynVec(items), nil\n}\n\n")
+	}
+
+	g.printf("func (v *%s) Deserialize(data []byte) error {\n", name)
+	if d.Kind == kindFixVec {
+		g.printf("items, err := types.DeserializeFixVec(data, %d)\n", g.size(d.Item))
+	} else {
+		g.printf("items, err := types.DeserializeDynVec(data)\n")
+	}
+	g.printf("if err != nil {\nreturn err\n}\n")
+	g.printf("*v = make(%s, len(items))\n", name)
+	g.printf("for i := 0; i < len(items); i++ {\n")
+	g.decode(d.Name, d.Item, "items[i]", "(*v)[i]")
+	g.printf("}\nreturn nil\n}\n")
+}
+
+func (g *generator) genOption(d *decl) {
+	name := exportName(d.Name)
+	item := g.goType(d.Item)
+
+	g.printf("// %s is None when Value is nil\n", name)
+	g.printf("type %s struct {\nValue *%s\n}\n\n", name, item)
+
+	if d.Item == primitiveByte {
+		g.printf("func (v *%s) Serialize() ([]byte, error) {\n", name)
+		g.printf("if v.Value == nil {\nreturn []byte{}, nil\n}\nreturn []byte{*v.Value}, nil\n}\n\n")
+		g.printf("func (v *%s) Deserialize(data []byte) error {\n", name)
+		g.printf("if len(data) == 0 {\nv.Value = nil\nreturn nil\n}\n")
+		g.printf("if len(data) != 1 {\nreturn %s\n}\n", g.verificationError(d.Name, "ErrTotalSizeNotMatch"))
+		g.printf("value := data[0]\nv.Value = &value\nreturn nil\n}\n")
+		return
+	}
+
+	g.printf("func (v *%s) Serialize() ([]byte, error) {\nreturn types.SerializeOption(v.Value)\n}\n\n", name)
+
+	g.printf("func (v *%s) Deserialize(data []byte) error {\n", name)
+	g.printf("value := new(%s)\n", item)
+	g.printf("some, err := types.DeserializeOption(data, value)\nif err != nil {\nreturn err\n}\n")
+	g.printf("if some {\nv.Value = value\n} else {\nv.Value = nil\n}\nreturn nil\n}\n")
+}
+
+func (g *generator) genUnion(d *decl) {
+	name := exportName(d.Name)
+
+	names := make([]string, len(d.Items))
+	for i, item := range d.Items {
+		names[i] = "*" + g.goType(item.Type)
+	}
+	g.printf("// %s holds one of %s\n", name, strings.Join(names, ", "))
+	g.printf("type %s struct {\nValue types.Serializer\n}\n\n", name)
+
+	g.printf("func (v *%s) Serialize() ([]byte, error) {\n", name)
+	g.printf("var id uint\n")
+	g.printf("switch v.Value.(type) {\n")
+	for _, item := range d.Items {
+		g.printf("case *%s:\nid = %d\n", g.goType(item.Type), item.ID)
+	}
+	g.printf("default:\nreturn nil, %s\n}\n", g.verificationError(d.Name, "ErrUnknownItem"))
+	g.printf("item, err := v.Value.Serialize()\nif err != nil {\nreturn nil, err\n}\n")
+	g.printf("return types.SerializeStruct([][]byte{types.SerializeUint(id), item}), nil\n}\n\n")
+
+	g.printf("func (v *%s) Deserialize(data []byte) error {\n", name)
+	g.printf("if len(data) < 4 {\nreturn %s\n}\n", g.verificationError(d.Name, "ErrHeaderIsBroken"))
+	g.printf("id, err := types.DeserializeUint(data[:4])\nif err != nil {\nreturn err\n}\n")
+	g.printf("switch id {\n")
+	for _, item := range d.Items {
+		g.printf("case %d:\nvalue := new(%s)\n", item.ID, g.goType(item.Type))
+		g.decode(d.Name, item.Type, "data[4:]", "value")
+		g.printf("v.Value = value\n")
+	}
+	g.printf("default:\nreturn %s\n}\nreturn nil\n}\n", g.verificationError(d.Name, "ErrUnknownItem"))
+}
+
+// exportName converts a molecule name like code_hash to an exported go name CodeHash
+func exportName(name string) string {
+	var b strings.Builder
+	upper := true
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if c == '_' {
+			upper = true
+			continue
+		}
+		if upper && c >= 'a' && c <= 'z' {
+			c -= 'a' - 'A'
+		}
+		upper = false
+		b.WriteByte(c)
+	}
+	if b.Len() == 0 || (b.String()[0] >= '0' && b.String()[0] <= '9') {
+		return "T" + b.String()
+	}
+	return b.String()
+}
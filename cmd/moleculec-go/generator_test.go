@@ -0,0 +1,78 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFile(t *testing.T) {
+	s, err := parseFile("testdata/blockchain.mol")
+	assert.Nil(t, err)
+
+	assert.Equal(t, kindArray, s.byName["Byte32"].Kind)
+	assert.Equal(t, 32, s.byName["Byte32"].Length)
+	assert.Equal(t, kindFixVec, s.byName["Bytes"].Kind)
+	assert.Equal(t, kindFixVec, s.byName["CellInputVec"].Kind)
+	assert.Equal(t, kindDynVec, s.byName["CellOutputVec"].Kind)
+	assert.Equal(t, kindOption, s.byName["ScriptOpt"].Kind)
+	assert.Equal(t, []unionItem{{"WitnessArgs", 0}, {"Script", 5}, {"OutPoint", 6}}, s.byName["WitnessLayout"].Items)
+
+	size, ok := s.size("CellInput")
+	assert.True(t, ok)
+	assert.Equal(t, 44, size)
+	_, ok = s.size("Script")
+	assert.False(t, ok)
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := map[string]string{
+		"array A [byte; 0];":                      "invalid array length",
+		"table A { a: B }":                        "undefined type B",
+		"vector A <byte>; vector A <byte>;":       "duplicate declaration A",
+		"vector A <byte>; struct B { a: A, }":     "must be fixed size",
+		"struct A { a: byte, a: byte }":           "duplicate field a",
+		"table A { a byte }":                      "expected \":\"",
+		"union A { }":                             "must have items",
+		"/* unterminated":                         "unterminated comment",
+		"import types;":                           "imports are not supported",
+		"array A [byte; 1]; union B { A, A: 0, }": "duplicate union id 0",
+	}
+	for src, want := range cases {
+		_, err := parseSchema(src)
+		if assert.Error(t, err, src) {
+			assert.Contains(t, err.Error(), want, src)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	s, err := parseFile("testdata/blockchain.mol")
+	assert.Nil(t, err)
+
+	src, err := generate(s, "blockchain", "blockchain.mol")
+	assert.Nil(t, err)
+
+	code := string(src)
+	assert.True(t, strings.HasPrefix(code, "// Code generated by moleculec-go. DO NOT EDIT."))
+	assert.Contains(t, code, "type Byte32 [32]byte")
+	assert.Contains(t, code, "type Bytes []byte")
+	assert.Contains(t, code, "return types.SerializeFixVec(items), nil")
+	assert.Contains(t, code, "items, err := types.DeserializeDynVec(data)")
+	assert.Contains(t, code, "fields, err := types.DeserializeStruct(data, []uint{8, 36})")
+	assert.Contains(t, code, "fields, err := types.DeserializeTable(data, 3)")
+	assert.Contains(t, code, "\tHashType byte\n")
+	assert.Contains(t, code, "case *Script:\n\t\tid = 5")
+}
+
+func TestGenerateSkipsImported(t *testing.T) {
+	s, err := parseSchema("array Byte32 [byte; 32]; table Cell { hash: Byte32 }")
+	assert.Nil(t, err)
+	s.byName["Byte32"].Imported = true
+
+	src, err := generate(s, "cell", "")
+	assert.Nil(t, err)
+	assert.NotContains(t, string(src), "type Byte32")
+	assert.Contains(t, string(src), "type Cell struct")
+}
@@ -0,0 +1,48 @@
+// Command moleculec-go generates go types with Serialize and Deserialize methods from a molecule schema.
+//
+// Usage:
+//
+//	moleculec-go -schema blockchain.mol -package blockchain -output blockchain.go
+//
+// Types declared in imported schemas are only used for resolving, they are expected to be generated
+// into the same go package from their own schema files.
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	schemaFile := flag.String("schema", "", "molecule schema file")
+	pkg := flag.String("package", "", "go package name of generated code")
+	output := flag.String("output", "", "output file, print to stdout if empty")
+	flag.Parse()
+
+	if *schemaFile == "" || *pkg == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	s, err := parseFile(*schemaFile)
+	if err != nil {
+		log.Fatalf("parse schema error: %v", err)
+	}
+
+	src, err := generate(s, *pkg, filepath.Base(*schemaFile))
+	if err != nil {
+		log.Fatalf("generate code error: %v", err)
+	}
+
+	if *output == "" {
+		_, err = os.Stdout.Write(src)
+	} else {
+		err = ioutil.WriteFile(*output, src, 0644)
+	}
+	if err != nil {
+		log.Fatalf("write code error: %v", err)
+	}
+}
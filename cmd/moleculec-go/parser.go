@@ -0,0 +1,583 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type declKind string
+
+const (
+	kindArray  declKind = "array"
+	kindStruct declKind = "struct"
+	kindFixVec declKind = "fixvec"
+	kindDynVec declKind = "dynvec"
+	kindTable  declKind = "table"
+	kindOption declKind = "option"
+	kindUnion  declKind = "union"
+
+	primitiveByte = "byte"
+)
+
+type field struct {
+	Name string
+	Type string
+}
+
+type unionItem struct {
+	Type string
+	ID   uint32
+}
+
+type decl struct {
+	Kind   declKind
+	Name   string
+	Item   string
+	Length int
+	Fields []field
+	Items  []unionItem
+	// Imported declarations are only used to resolve types, no code is generated for them
+	Imported bool
+}
+
+type schema struct {
+	Decls  []*decl
+	byName map[string]*decl
+}
+
+// parseFile parse a molecule schema file, imports are resolved relative to the file.
+func parseFile(path string) (*schema, error) {
+	s := &schema{byName: make(map[string]*decl)}
+	if err := s.load(path, false, make(map[string]bool)); err != nil {
+		return nil, err
+	}
+	if err := s.resolve(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// parseSchema parse molecule schema source which has no imports.
+func parseSchema(src string) (*schema, error) {
+	s := &schema{byName: make(map[string]*decl)}
+	imports, err := s.parse(src, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(imports) > 0 {
+		return nil, errors.New("imports are not supported without a schema file")
+	}
+	if err := s.resolve(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *schema) load(path string, imported bool, visited map[string]bool) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	if visited[abs] {
+		return nil
+	}
+	visited[abs] = true
+
+	src, err := ioutil.ReadFile(abs)
+	if err != nil {
+		return err
+	}
+
+	// Parse the file first so a loop of imports is cut by visited
+	var local schema
+	local.byName = make(map[string]*decl)
+	imports, err := local.parse(string(src), imported)
+	if err != nil {
+		return fmt.Errorf("parse %s error: %v", path, err)
+	}
+
+	for _, imp := range imports {
+		if err := s.load(filepath.Join(filepath.Dir(abs), imp+".mol"), true, visited); err != nil {
+			return err
+		}
+	}
+
+	for _, d := range local.Decls {
+		if err := s.add(d); err != nil {
+			return fmt.Errorf("parse %s error: %v", path, err)
+		}
+	}
+	return nil
+}
+
+func (s *schema) add(d *decl) error {
+	if d.Name == primitiveByte {
+		return fmt.Errorf("%s is a primitive type", d.Name)
+	}
+	if _, ok := s.byName[d.Name]; ok {
+		return fmt.Errorf("duplicate declaration %s", d.Name)
+	}
+	s.byName[d.Name] = d
+	s.Decls = append(s.Decls, d)
+	return nil
+}
+
+// parse parse declarations into s and returns the imported schema paths
+func (s *schema) parse(src string, imported bool) ([]string, error) {
+	p := &parser{lexer: newLexer(src)}
+	var imports []string
+	for {
+		tok, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		if tok == "" {
+			return imports, nil
+		}
+
+		var d *decl
+		switch tok {
+		case "import":
+			path, err := p.lexer.until(';')
+			if err != nil {
+				return nil, err
+			}
+			imports = append(imports, path)
+			continue
+		case "array":
+			d, err = p.parseArray()
+		case "struct", "table":
+			d, err = p.parseFields(declKind(tok))
+		case "vector":
+			d, err = p.parseVector()
+		case "option":
+			d, err = p.parseOption()
+		case "union":
+			d, err = p.parseUnion()
+		default:
+			return nil, fmt.Errorf("line %d: unexpected %q", p.lexer.line, tok)
+		}
+		if err != nil {
+			return nil, err
+		}
+		d.Imported = imported
+		if err := s.add(d); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// resolve check all referenced types and split vectors into fixvec and dynvec
+func (s *schema) resolve() error {
+	for _, d := range s.Decls {
+		switch d.Kind {
+		case kindArray, kindFixVec, kindOption:
+			if err := s.check(d.Name, d.Item); err != nil {
+				return err
+			}
+		case kindStruct, kindTable:
+			names := make(map[string]bool)
+			for _, f := range d.Fields {
+				if names[f.Name] {
+					return fmt.Errorf("%s: duplicate field %s", d.Name, f.Name)
+				}
+				names[f.Name] = true
+				if err := s.check(d.Name, f.Type); err != nil {
+					return err
+				}
+			}
+		case kindUnion:
+			ids := make(map[uint32]bool)
+			for _, item := range d.Items {
+				if ids[item.ID] {
+					return fmt.Errorf("%s: duplicate union id %d", d.Name, item.ID)
+				}
+				ids[item.ID] = true
+				if item.Type == primitiveByte {
+					return fmt.Errorf("%s: union item can not be byte", d.Name)
+				}
+				if err := s.check(d.Name, item.Type); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for _, d := range s.Decls {
+		switch d.Kind {
+		case kindArray:
+			if _, ok := s.size(d.Item); !ok {
+				return fmt.Errorf("%s: array item %s must be fixed size", d.Name, d.Item)
+			}
+		case kindStruct:
+			for _, f := range d.Fields {
+				if _, ok := s.size(f.Type); !ok {
+					return fmt.Errorf("%s: struct field %s must be fixed size", d.Name, f.Name)
+				}
+			}
+		case kindFixVec:
+			if _, ok := s.size(d.Item); !ok {
+				d.Kind = kindDynVec
+			}
+		}
+	}
+	return nil
+}
+
+func (s *schema) check(name, typ string) error {
+	if typ == primitiveByte {
+		return nil
+	}
+	if _, ok := s.byName[typ]; !ok {
+		return fmt.Errorf("%s: undefined type %s", name, typ)
+	}
+	return nil
+}
+
+// size returns the size of fixed size types: byte, array and struct
+func (s *schema) size(typ string) (int, bool) {
+	return s.sizeOf(typ, make(map[string]bool))
+}
+
+func (s *schema) sizeOf(typ string, visiting map[string]bool) (int, bool) {
+	if typ == primitiveByte {
+		return 1, true
+	}
+	d, ok := s.byName[typ]
+	if !ok || visiting[typ] {
+		return 0, false
+	}
+	visiting[typ] = true
+	defer delete(visiting, typ)
+
+	switch d.Kind {
+	case kindArray:
+		size, ok := s.sizeOf(d.Item, visiting)
+		return size * d.Length, ok
+	case kindStruct:
+		total := 0
+		for _, f := range d.Fields {
+			size, ok := s.sizeOf(f.Type, visiting)
+			if !ok {
+				return 0, false
+			}
+			total += size
+		}
+		return total, true
+	}
+	return 0, false
+}
+
+type parser struct {
+	lexer *lexer
+}
+
+func (p *parser) next() (string, error) {
+	return p.lexer.next()
+}
+
+func (p *parser) expect(want string) error {
+	tok, err := p.next()
+	if err != nil {
+		return err
+	}
+	if tok != want {
+		return fmt.Errorf("line %d: expected %q, got %q", p.lexer.line, want, tok)
+	}
+	return nil
+}
+
+func (p *parser) ident() (string, error) {
+	tok, err := p.next()
+	if err != nil {
+		return "", err
+	}
+	if !isIdent(tok) {
+		return "", fmt.Errorf("line %d: expected identifier, got %q", p.lexer.line, tok)
+	}
+	return tok, nil
+}
+
+// array Name [Item; N];
+func (p *parser) parseArray() (*decl, error) {
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect("["); err != nil {
+		return nil, err
+	}
+	item, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(";"); err != nil {
+		return nil, err
+	}
+	tok, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	length, err := strconv.Atoi(tok)
+	if err != nil || length <= 0 {
+		return nil, fmt.Errorf("line %d: invalid array length %q", p.lexer.line, tok)
+	}
+	if err := p.expect("]"); err != nil {
+		return nil, err
+	}
+	if err := p.expect(";"); err != nil {
+		return nil, err
+	}
+	return &decl{Kind: kindArray, Name: name, Item: item, Length: length}, nil
+}
+
+// struct Name { field: Type, ... } or table Name { field: Type, ... }
+func (p *parser) parseFields(kind declKind) (*decl, error) {
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	d := &decl{Kind: kind, Name: name}
+	for {
+		tok, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		if tok == "}" {
+			break
+		}
+		if !isIdent(tok) {
+			return nil, fmt.Errorf("line %d: expected field name, got %q", p.lexer.line, tok)
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		typ, err := p.ident()
+		if err != nil {
+			return nil, err
+		}
+		d.Fields = append(d.Fields, field{Name: tok, Type: typ})
+
+		tok, err = p.next()
+		if err != nil {
+			return nil, err
+		}
+		if tok == "}" {
+			break
+		}
+		if tok != "," {
+			return nil, fmt.Errorf("line %d: expected \",\" or \"}\", got %q", p.lexer.line, tok)
+		}
+	}
+	if kind == kindStruct && len(d.Fields) == 0 {
+		return nil, fmt.Errorf("struct %s must have fields", name)
+	}
+	return d, nil
+}
+
+// vector Name <Item>;
+func (p *parser) parseVector() (*decl, error) {
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect("<"); err != nil {
+		return nil, err
+	}
+	item, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(">"); err != nil {
+		return nil, err
+	}
+	if err := p.expect(";"); err != nil {
+		return nil, err
+	}
+	// Fixed or dynamic is decided after all types are known
+	return &decl{Kind: kindFixVec, Name: name, Item: item}, nil
+}
+
+// option Name (Item);
+func (p *parser) parseOption() (*decl, error) {
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	item, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	if err := p.expect(";"); err != nil {
+		return nil, err
+	}
+	return &decl{Kind: kindOption, Name: name, Item: item}, nil
+}
+
+// union Name { Item, Item: id, ... }
+func (p *parser) parseUnion() (*decl, error) {
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	d := &decl{Kind: kindUnion, Name: name}
+	var id uint32
+	for {
+		tok, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		if tok == "}" {
+			break
+		}
+		if !isIdent(tok) {
+			return nil, fmt.Errorf("line %d: expected union item, got %q", p.lexer.line, tok)
+		}
+		item := unionItem{Type: tok, ID: id}
+
+		tok, err = p.next()
+		if err != nil {
+			return nil, err
+		}
+		if tok == ":" {
+			tok, err = p.next()
+			if err != nil {
+				return nil, err
+			}
+			custom, err := strconv.ParseUint(tok, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid union id %q", p.lexer.line, tok)
+			}
+			item.ID = uint32(custom)
+
+			tok, err = p.next()
+			if err != nil {
+				return nil, err
+			}
+		}
+		d.Items = append(d.Items, item)
+		id = item.ID + 1
+
+		if tok == "}" {
+			break
+		}
+		if tok != "," {
+			return nil, fmt.Errorf("line %d: expected \",\" or \"}\", got %q", p.lexer.line, tok)
+		}
+	}
+	if len(d.Items) == 0 {
+		return nil, fmt.Errorf("union %s must have items", name)
+	}
+	return d, nil
+}
+
+type lexer struct {
+	src  string
+	pos  int
+	line int
+}
+
+func newLexer(src string) *lexer {
+	return &lexer{src: src, line: 1}
+}
+
+// next returns the next token, or an empty string at the end of source
+func (l *lexer) next() (string, error) {
+	if err := l.skip(); err != nil {
+		return "", err
+	}
+	if l.pos >= len(l.src) {
+		return "", nil
+	}
+
+	c := l.src[l.pos]
+	if strings.IndexByte("{}[]()<>;,:", c) >= 0 {
+		l.pos++
+		return string(c), nil
+	}
+	if isIdentChar(c) {
+		start := l.pos
+		for l.pos < len(l.src) && isIdentChar(l.src[l.pos]) {
+			l.pos++
+		}
+		return l.src[start:l.pos], nil
+	}
+	return "", fmt.Errorf("line %d: unexpected character %q", l.line, c)
+}
+
+// until returns the trimmed text before the delimiter and consumes the delimiter
+func (l *lexer) until(delim byte) (string, error) {
+	if err := l.skip(); err != nil {
+		return "", err
+	}
+	end := strings.IndexByte(l.src[l.pos:], delim)
+	if end < 0 {
+		return "", fmt.Errorf("line %d: missing %q", l.line, delim)
+	}
+	text := strings.TrimSpace(l.src[l.pos : l.pos+end])
+	l.line += strings.Count(text, "\n")
+	l.pos += end + 1
+	return text, nil
+}
+
+// skip skips whitespaces and comments
+func (l *lexer) skip() error {
+	for l.pos < len(l.src) {
+		switch {
+		case l.src[l.pos] == '\n':
+			l.line++
+			l.pos++
+		case l.src[l.pos] == ' ' || l.src[l.pos] == '\t' || l.src[l.pos] == '\r':
+			l.pos++
+		case strings.HasPrefix(l.src[l.pos:], "//"):
+			end := strings.IndexByte(l.src[l.pos:], '\n')
+			if end < 0 {
+				l.pos = len(l.src)
+			} else {
+				l.pos += end
+			}
+		case strings.HasPrefix(l.src[l.pos:], "/*"):
+			end := strings.Index(l.src[l.pos+2:], "*/")
+			if end < 0 {
+				return fmt.Errorf("line %d: unterminated comment", l.line)
+			}
+			l.line += strings.Count(l.src[l.pos:l.pos+2+end], "\n")
+			l.pos += end + 4
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func isIdent(tok string) bool {
+	if tok == "" || (tok[0] >= '0' && tok[0] <= '9') {
+		return false
+	}
+	for i := 0; i < len(tok); i++ {
+		if !isIdentChar(tok[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ququzone/ckb-sdk-go/cmd/moleculec-go/testdata/blockchain"
+	"github.com/ququzone/ckb-sdk-go/types"
+)
+
+//go:generate go run . -schema testdata/blockchain.mol -package blockchain -output testdata/blockchain/blockchain_gen.go
+
+func TestGeneratedUpToDate(t *testing.T) {
+	s, err := parseFile("testdata/blockchain.mol")
+	assert.Nil(t, err)
+	src, err := generate(s, "blockchain", "blockchain.mol")
+	assert.Nil(t, err)
+
+	committed, err := ioutil.ReadFile("testdata/blockchain/blockchain_gen.go")
+	assert.Nil(t, err)
+	assert.Equal(t, string(committed), string(src), "run go generate to update testdata/blockchain")
+}
+
+func TestGeneratedScript(t *testing.T) {
+	script := &types.Script{
+		CodeHash: types.HexToHash("0x9bd7e06f3ecf4be0f2fcd2188b23f1b9fcc88e5d4b65a8637b17723bbda3cce8"),
+		HashType: types.HashTypeType,
+		Args:     common.FromHex("0xedcda9513fa030ce4308e29245a22c022d0443bb"),
+	}
+	expected, err := script.Serialize()
+	assert.Nil(t, err)
+
+	generated := &blockchain.Script{
+		CodeHash: blockchain.Byte32(script.CodeHash),
+		HashType: 1,
+		Args:     blockchain.Bytes(script.Args),
+	}
+	data, err := generated.Serialize()
+	assert.Nil(t, err)
+	assert.Equal(t, expected, data)
+
+	var decoded blockchain.Script
+	assert.Nil(t, decoded.Deserialize(expected))
+	assert.Equal(t, generated, &decoded)
+}
+
+func TestGeneratedTransaction(t *testing.T) {
+	lock := &types.Script{
+		CodeHash: types.HexToHash("0x9bd7e06f3ecf4be0f2fcd2188b23f1b9fcc88e5d4b65a8637b17723bbda3cce8"),
+		HashType: types.HashTypeType,
+		Args:     common.FromHex("0xedcda9513fa030ce4308e29245a22c022d0443bb"),
+	}
+	tx := &types.Transaction{
+		Version:    0,
+		HeaderDeps: []types.Hash{types.HexToHash("0x386bafd53bade6bf769c9b10f545e31ea744cb6ebc5f1c8178f307e8dce157a6")},
+		CellDeps: []*types.CellDep{
+			{
+				OutPoint: &types.OutPoint{TxHash: types.HexToHash("0xace5ea83c478bb866edf122ff862085789158f5cbff155b7bb5f13058555b708"), Index: 0},
+				DepType:  types.DepTypeDepGroup,
+			},
+		},
+		Inputs: []*types.CellInput{
+			{
+				Since:          0x2000000000000000,
+				PreviousOutput: &types.OutPoint{TxHash: types.HexToHash("0xc8cfe3d09b0a50fd2df3bd79dbadca23b7eb1f58087942d7266abea93459fce1"), Index: 1},
+			},
+		},
+		Outputs: []*types.CellOutput{
+			{
+				Capacity: 400000000000,
+				Lock:     lock,
+				Type: &types.Script{
+					CodeHash: types.HexToHash("0x82d76d1b75fe2fd9a27dfbaa65a039221a380d76c926f378d3f81cf3e7e13f2e"),
+					HashType: types.HashTypeType,
+					Args:     []byte{},
+				},
+			},
+			{Capacity: 99999997000, Lock: lock},
+		},
+		OutputsData: [][]byte{make([]byte, 8), {}},
+		Witnesses:   [][]byte{make([]byte, 85), {}},
+	}
+
+	raw, err := tx.Serialize()
+	assert.Nil(t, err)
+	var decodedRaw blockchain.RawTransaction
+	assert.Nil(t, decodedRaw.Deserialize(raw))
+	assert.Equal(t, 1, len(decodedRaw.CellDeps))
+	assert.Equal(t, byte(1), decodedRaw.CellDeps[0].DepType)
+	assert.Equal(t, blockchain.Uint32{1, 0, 0, 0}, decodedRaw.Inputs[0].PreviousOutput.Index)
+	assert.Equal(t, 2, len(decodedRaw.Outputs))
+	assert.NotNil(t, decodedRaw.Outputs[0].Type.Value)
+	assert.Nil(t, decodedRaw.Outputs[1].Type.Value)
+	data, err := decodedRaw.Serialize()
+	assert.Nil(t, err)
+	assert.Equal(t, raw, data)
+
+	full, err := tx.SerializeWithWitnesses()
+	assert.Nil(t, err)
+	var decoded blockchain.Transaction
+	assert.Nil(t, decoded.Deserialize(full))
+	assert.Equal(t, decodedRaw, decoded.Raw)
+	assert.Equal(t, blockchain.BytesVec{make([]byte, 85), {}}, decoded.Witnesses)
+	data, err = decoded.Serialize()
+	assert.Nil(t, err)
+	assert.Equal(t, full, data)
+}
+
+func TestGeneratedWitnessArgs(t *testing.T) {
+	for _, w := range []*types.WitnessArgs{
+		{},
+		{Lock: make([]byte, 65)},
+		{Lock: []byte{}, InputType: []byte{1}, OutputType: []byte{2, 3}},
+	} {
+		expected, err := w.Serialize()
+		assert.Nil(t, err)
+
+		generated := &blockchain.WitnessArgs{}
+		for _, field := range []struct {
+			from []byte
+			to   *blockchain.BytesOpt
+		}{
+			{w.Lock, &generated.Lock},
+			{w.InputType, &generated.InputType},
+			{w.OutputType, &generated.OutputType},
+		} {
+			if field.from != nil {
+				value := blockchain.Bytes(field.from)
+				field.to.Value = &value
+			}
+		}
+		data, err := generated.Serialize()
+		assert.Nil(t, err)
+		assert.Equal(t, expected, data)
+
+		var decoded blockchain.WitnessArgs
+		assert.Nil(t, decoded.Deserialize(expected))
+		assert.Equal(t, generated, &decoded)
+	}
+}
+
+func TestGeneratedMalformedByteField(t *testing.T) {
+	// a Script table whose hash_type field is empty
+	data := types.SerializeTable([][]byte{make([]byte, 32), {}, types.SerializeBytes([]byte{})})
+	var script blockchain.Script
+	err := script.Deserialize(data)
+	if assert.Error(t, err) {
+		assert.True(t, errors.Is(err, types.ErrTotalSizeNotMatch))
+	}
+}
@@ -0,0 +1,624 @@
+// Code generated by moleculec-go. DO NOT EDIT.
+// Source: blockchain.mol
+
+package blockchain
+
+import (
+	"github.com/ququzone/ckb-sdk-go/types"
+)
+
+type Uint32 [4]byte
+
+func (v *Uint32) Serialize() ([]byte, error) {
+	return v[:], nil
+}
+
+func (v *Uint32) Deserialize(data []byte) error {
+	if len(data) != 4 {
+		return &types.VerificationError{Name: "Uint32", Err: types.ErrTotalSizeNotMatch}
+	}
+	copy(v[:], data)
+	return nil
+}
+
+type Uint64 [8]byte
+
+func (v *Uint64) Serialize() ([]byte, error) {
+	return v[:], nil
+}
+
+func (v *Uint64) Deserialize(data []byte) error {
+	if len(data) != 8 {
+		return &types.VerificationError{Name: "Uint64", Err: types.ErrTotalSizeNotMatch}
+	}
+	copy(v[:], data)
+	return nil
+}
+
+type Byte32 [32]byte
+
+func (v *Byte32) Serialize() ([]byte, error) {
+	return v[:], nil
+}
+
+func (v *Byte32) Deserialize(data []byte) error {
+	if len(data) != 32 {
+		return &types.VerificationError{Name: "Byte32", Err: types.ErrTotalSizeNotMatch}
+	}
+	copy(v[:], data)
+	return nil
+}
+
+type Bytes []byte
+
+func (v *Bytes) Serialize() ([]byte, error) {
+	return types.SerializeBytes(*v), nil
+}
+
+func (v *Bytes) Deserialize(data []byte) error {
+	b, err := types.DeserializeBytes(data)
+	if err != nil {
+		return err
+	}
+	*v = b
+	return nil
+}
+
+// BytesOpt is None when Value is nil
+type BytesOpt struct {
+	Value *Bytes
+}
+
+func (v *BytesOpt) Serialize() ([]byte, error) {
+	return types.SerializeOption(v.Value)
+}
+
+func (v *BytesOpt) Deserialize(data []byte) error {
+	value := new(Bytes)
+	some, err := types.DeserializeOption(data, value)
+	if err != nil {
+		return err
+	}
+	if some {
+		v.Value = value
+	} else {
+		v.Value = nil
+	}
+	return nil
+}
+
+type BytesVec []Bytes
+
+func (v *BytesVec) Serialize() ([]byte, error) {
+	items := make([][]byte, len(*v))
+	var err error
+	for i := 0; i < len(*v); i++ {
+		items[i], err = (*v)[i].Serialize()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return types.SerializeDynVec(items), nil
+}
+
+func (v *BytesVec) Deserialize(data []byte) error {
+	items, err := types.DeserializeDynVec(data)
+	if err != nil {
+		return err
+	}
+	*v = make(BytesVec, len(items))
+	for i := 0; i < len(items); i++ {
+		if err := (*v)[i].Deserialize(items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type Byte32Vec []Byte32
+
+func (v *Byte32Vec) Serialize() ([]byte, error) {
+	items := make([][]byte, len(*v))
+	var err error
+	for i := 0; i < len(*v); i++ {
+		items[i], err = (*v)[i].Serialize()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return types.SerializeFixVec(items), nil
+}
+
+func (v *Byte32Vec) Deserialize(data []byte) error {
+	items, err := types.DeserializeFixVec(data, 32)
+	if err != nil {
+		return err
+	}
+	*v = make(Byte32Vec, len(items))
+	for i := 0; i < len(items); i++ {
+		if err := (*v)[i].Deserialize(items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ScriptOpt is None when Value is nil
+type ScriptOpt struct {
+	Value *Script
+}
+
+func (v *ScriptOpt) Serialize() ([]byte, error) {
+	return types.SerializeOption(v.Value)
+}
+
+func (v *ScriptOpt) Deserialize(data []byte) error {
+	value := new(Script)
+	some, err := types.DeserializeOption(data, value)
+	if err != nil {
+		return err
+	}
+	if some {
+		v.Value = value
+	} else {
+		v.Value = nil
+	}
+	return nil
+}
+
+type Script struct {
+	CodeHash Byte32
+	HashType byte
+	Args     Bytes
+}
+
+func (v *Script) Serialize() ([]byte, error) {
+	fields := make([][]byte, 3)
+	var err error
+	fields[0], err = v.CodeHash.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	fields[1] = []byte{v.HashType}
+	fields[2], err = v.Args.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	return types.SerializeTable(fields), nil
+}
+
+func (v *Script) Deserialize(data []byte) error {
+	fields, err := types.DeserializeTable(data, 3)
+	if err != nil {
+		return err
+	}
+	if err := v.CodeHash.Deserialize(fields[0]); err != nil {
+		return err
+	}
+	if len(fields[1]) != 1 {
+		return &types.VerificationError{Name: "Script", Err: types.ErrTotalSizeNotMatch}
+	}
+	v.HashType = fields[1][0]
+	if err := v.Args.Deserialize(fields[2]); err != nil {
+		return err
+	}
+	return nil
+}
+
+type OutPoint struct {
+	TxHash Byte32
+	Index  Uint32
+}
+
+func (v *OutPoint) Serialize() ([]byte, error) {
+	fields := make([][]byte, 2)
+	var err error
+	fields[0], err = v.TxHash.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	fields[1], err = v.Index.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	return types.SerializeStruct(fields), nil
+}
+
+func (v *OutPoint) Deserialize(data []byte) error {
+	fields, err := types.DeserializeStruct(data, []uint{32, 4})
+	if err != nil {
+		return err
+	}
+	if err := v.TxHash.Deserialize(fields[0]); err != nil {
+		return err
+	}
+	if err := v.Index.Deserialize(fields[1]); err != nil {
+		return err
+	}
+	return nil
+}
+
+type CellInput struct {
+	Since          Uint64
+	PreviousOutput OutPoint
+}
+
+func (v *CellInput) Serialize() ([]byte, error) {
+	fields := make([][]byte, 2)
+	var err error
+	fields[0], err = v.Since.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	fields[1], err = v.PreviousOutput.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	return types.SerializeStruct(fields), nil
+}
+
+func (v *CellInput) Deserialize(data []byte) error {
+	fields, err := types.DeserializeStruct(data, []uint{8, 36})
+	if err != nil {
+		return err
+	}
+	if err := v.Since.Deserialize(fields[0]); err != nil {
+		return err
+	}
+	if err := v.PreviousOutput.Deserialize(fields[1]); err != nil {
+		return err
+	}
+	return nil
+}
+
+type CellOutput struct {
+	Capacity Uint64
+	Lock     Script
+	Type     ScriptOpt
+}
+
+func (v *CellOutput) Serialize() ([]byte, error) {
+	fields := make([][]byte, 3)
+	var err error
+	fields[0], err = v.Capacity.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	fields[1], err = v.Lock.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	fields[2], err = v.Type.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	return types.SerializeTable(fields), nil
+}
+
+func (v *CellOutput) Deserialize(data []byte) error {
+	fields, err := types.DeserializeTable(data, 3)
+	if err != nil {
+		return err
+	}
+	if err := v.Capacity.Deserialize(fields[0]); err != nil {
+		return err
+	}
+	if err := v.Lock.Deserialize(fields[1]); err != nil {
+		return err
+	}
+	if err := v.Type.Deserialize(fields[2]); err != nil {
+		return err
+	}
+	return nil
+}
+
+type CellDep struct {
+	OutPoint OutPoint
+	DepType  byte
+}
+
+func (v *CellDep) Serialize() ([]byte, error) {
+	fields := make([][]byte, 2)
+	var err error
+	fields[0], err = v.OutPoint.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	fields[1] = []byte{v.DepType}
+	return types.SerializeStruct(fields), nil
+}
+
+func (v *CellDep) Deserialize(data []byte) error {
+	fields, err := types.DeserializeStruct(data, []uint{36, 1})
+	if err != nil {
+		return err
+	}
+	if err := v.OutPoint.Deserialize(fields[0]); err != nil {
+		return err
+	}
+	if len(fields[1]) != 1 {
+		return &types.VerificationError{Name: "CellDep", Err: types.ErrTotalSizeNotMatch}
+	}
+	v.DepType = fields[1][0]
+	return nil
+}
+
+type CellDepVec []CellDep
+
+func (v *CellDepVec) Serialize() ([]byte, error) {
+	items := make([][]byte, len(*v))
+	var err error
+	for i := 0; i < len(*v); i++ {
+		items[i], err = (*v)[i].Serialize()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return types.SerializeFixVec(items), nil
+}
+
+func (v *CellDepVec) Deserialize(data []byte) error {
+	items, err := types.DeserializeFixVec(data, 37)
+	if err != nil {
+		return err
+	}
+	*v = make(CellDepVec, len(items))
+	for i := 0; i < len(items); i++ {
+		if err := (*v)[i].Deserialize(items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type CellInputVec []CellInput
+
+func (v *CellInputVec) Serialize() ([]byte, error) {
+	items := make([][]byte, len(*v))
+	var err error
+	for i := 0; i < len(*v); i++ {
+		items[i], err = (*v)[i].Serialize()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return types.SerializeFixVec(items), nil
+}
+
+func (v *CellInputVec) Deserialize(data []byte) error {
+	items, err := types.DeserializeFixVec(data, 44)
+	if err != nil {
+		return err
+	}
+	*v = make(CellInputVec, len(items))
+	for i := 0; i < len(items); i++ {
+		if err := (*v)[i].Deserialize(items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type CellOutputVec []CellOutput
+
+func (v *CellOutputVec) Serialize() ([]byte, error) {
+	items := make([][]byte, len(*v))
+	var err error
+	for i := 0; i < len(*v); i++ {
+		items[i], err = (*v)[i].Serialize()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return types.SerializeDynVec(items), nil
+}
+
+func (v *CellOutputVec) Deserialize(data []byte) error {
+	items, err := types.DeserializeDynVec(data)
+	if err != nil {
+		return err
+	}
+	*v = make(CellOutputVec, len(items))
+	for i := 0; i < len(items); i++ {
+		if err := (*v)[i].Deserialize(items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type RawTransaction struct {
+	Version     Uint32
+	CellDeps    CellDepVec
+	HeaderDeps  Byte32Vec
+	Inputs      CellInputVec
+	Outputs     CellOutputVec
+	OutputsData BytesVec
+}
+
+func (v *RawTransaction) Serialize() ([]byte, error) {
+	fields := make([][]byte, 6)
+	var err error
+	fields[0], err = v.Version.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	fields[1], err = v.CellDeps.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	fields[2], err = v.HeaderDeps.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	fields[3], err = v.Inputs.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	fields[4], err = v.Outputs.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	fields[5], err = v.OutputsData.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	return types.SerializeTable(fields), nil
+}
+
+func (v *RawTransaction) Deserialize(data []byte) error {
+	fields, err := types.DeserializeTable(data, 6)
+	if err != nil {
+		return err
+	}
+	if err := v.Version.Deserialize(fields[0]); err != nil {
+		return err
+	}
+	if err := v.CellDeps.Deserialize(fields[1]); err != nil {
+		return err
+	}
+	if err := v.HeaderDeps.Deserialize(fields[2]); err != nil {
+		return err
+	}
+	if err := v.Inputs.Deserialize(fields[3]); err != nil {
+		return err
+	}
+	if err := v.Outputs.Deserialize(fields[4]); err != nil {
+		return err
+	}
+	if err := v.OutputsData.Deserialize(fields[5]); err != nil {
+		return err
+	}
+	return nil
+}
+
+type Transaction struct {
+	Raw       RawTransaction
+	Witnesses BytesVec
+}
+
+func (v *Transaction) Serialize() ([]byte, error) {
+	fields := make([][]byte, 2)
+	var err error
+	fields[0], err = v.Raw.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	fields[1], err = v.Witnesses.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	return types.SerializeTable(fields), nil
+}
+
+func (v *Transaction) Deserialize(data []byte) error {
+	fields, err := types.DeserializeTable(data, 2)
+	if err != nil {
+		return err
+	}
+	if err := v.Raw.Deserialize(fields[0]); err != nil {
+		return err
+	}
+	if err := v.Witnesses.Deserialize(fields[1]); err != nil {
+		return err
+	}
+	return nil
+}
+
+type WitnessArgs struct {
+	Lock       BytesOpt
+	InputType  BytesOpt
+	OutputType BytesOpt
+}
+
+func (v *WitnessArgs) Serialize() ([]byte, error) {
+	fields := make([][]byte, 3)
+	var err error
+	fields[0], err = v.Lock.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	fields[1], err = v.InputType.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	fields[2], err = v.OutputType.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	return types.SerializeTable(fields), nil
+}
+
+func (v *WitnessArgs) Deserialize(data []byte) error {
+	fields, err := types.DeserializeTable(data, 3)
+	if err != nil {
+		return err
+	}
+	if err := v.Lock.Deserialize(fields[0]); err != nil {
+		return err
+	}
+	if err := v.InputType.Deserialize(fields[1]); err != nil {
+		return err
+	}
+	if err := v.OutputType.Deserialize(fields[2]); err != nil {
+		return err
+	}
+	return nil
+}
+
+// WitnessLayout holds one of *WitnessArgs, *Script, *OutPoint
+type WitnessLayout struct {
+	Value types.Serializer
+}
+
+func (v *WitnessLayout) Serialize() ([]byte, error) {
+	var id uint
+	switch v.Value.(type) {
+	case *WitnessArgs:
+		id = 0
+	case *Script:
+		id = 5
+	case *OutPoint:
+		id = 6
+	default:
+		return nil, &types.VerificationError{Name: "WitnessLayout", Err: types.ErrUnknownItem}
+	}
+	item, err := v.Value.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	return types.SerializeStruct([][]byte{types.SerializeUint(id), item}), nil
+}
+
+func (v *WitnessLayout) Deserialize(data []byte) error {
+	if len(data) < 4 {
+		return &types.VerificationError{Name: "WitnessLayout", Err: types.ErrHeaderIsBroken}
+	}
+	id, err := types.DeserializeUint(data[:4])
+	if err != nil {
+		return err
+	}
+	switch id {
+	case 0:
+		value := new(WitnessArgs)
+		if err := value.Deserialize(data[4:]); err != nil {
+			return err
+		}
+		v.Value = value
+	case 5:
+		value := new(Script)
+		if err := value.Deserialize(data[4:]); err != nil {
+			return err
+		}
+		v.Value = value
+	case 6:
+		value := new(OutPoint)
+		if err := value.Deserialize(data[4:]); err != nil {
+			return err
+		}
+		v.Value = value
+	default:
+		return &types.VerificationError{Name: "WitnessLayout", Err: types.ErrUnknownItem}
+	}
+	return nil
+}
@@ -8,26 +8,56 @@ import (
 
 const charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
 
+// Encoding is the checksum variant, BECH32M is defined by BIP-350
+type Encoding uint
+
+const (
+	BECH32 Encoding = iota
+	BECH32M
+)
+
+const bech32mConst = 0x2bc830a3
+
 var gen = []int{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
 
+func (e Encoding) checksumConst() int {
+	if e == BECH32M {
+		return bech32mConst
+	}
+	return 1
+}
+
+// Decode decodes a bech32 string, bech32m strings are rejected
 func Decode(bech string) (string, []byte, error) {
+	encoding, hrp, decoded, err := DecodeWithEncoding(bech)
+	if err != nil {
+		return "", nil, err
+	}
+	if encoding != BECH32 {
+		return "", nil, errors.New("checksum failed. bech32m is not allowed")
+	}
+	return hrp, decoded, nil
+}
+
+// DecodeWithEncoding decodes a bech32 or bech32m string and returns which one it is
+func DecodeWithEncoding(bech string) (Encoding, string, []byte, error) {
 	for i := 0; i < len(bech); i++ {
 		if bech[i] < 33 || bech[i] > 126 {
-			return "", nil, fmt.Errorf("invalid character: '%c'", bech[i])
+			return BECH32, "", nil, fmt.Errorf("invalid character: '%c'", bech[i])
 		}
 	}
 
 	lower := strings.ToLower(bech)
 	upper := strings.ToUpper(bech)
 	if bech != lower && bech != upper {
-		return "", nil, errors.New("string not all lowercase or all uppercase")
+		return BECH32, "", nil, errors.New("string not all lowercase or all uppercase")
 	}
 
 	bech = lower
 
 	one := strings.LastIndexByte(bech, '1')
 	if one < 1 || one+7 > len(bech) {
-		return "", nil, fmt.Errorf("invalid index of 1")
+		return BECH32, "", nil, fmt.Errorf("invalid index of 1")
 	}
 
 	hrp := bech[:one]
@@ -35,25 +65,31 @@ func Decode(bech string) (string, []byte, error) {
 
 	decoded, err := toBytes(data)
 	if err != nil {
-		return "", nil, errors.New(fmt.Sprintf("failed converting data to bytes: %v", err))
+		return BECH32, "", nil, errors.New(fmt.Sprintf("failed converting data to bytes: %v", err))
 	}
 
-	if !bech32VerifyChecksum(hrp, decoded) {
+	encoding, ok := bech32VerifyChecksum(hrp, decoded)
+	if !ok {
 		moreInfo := ""
 		checksum := bech[len(bech)-6:]
 		expected, err := toChars(bech32Checksum(hrp,
-			decoded[:len(decoded)-6]))
+			decoded[:len(decoded)-6], BECH32))
 		if err == nil {
 			moreInfo = fmt.Sprintf("Expected %v, got %v.", expected, checksum)
 		}
-		return "", nil, errors.New("checksum failed. " + moreInfo)
+		return BECH32, "", nil, errors.New("checksum failed. " + moreInfo)
 	}
 
-	return hrp, decoded[:len(decoded)-6], nil
+	return encoding, hrp, decoded[:len(decoded)-6], nil
 }
 
+// Encode encodes data with bech32 checksum
 func Encode(hrp string, data []byte) (string, error) {
-	checksum := bech32Checksum(hrp, data)
+	return EncodeWithEncoding(hrp, data, BECH32)
+}
+
+func EncodeWithEncoding(hrp string, data []byte, encoding Encoding) (string, error) {
+	checksum := bech32Checksum(hrp, data, encoding)
 	combined := append(data, checksum...)
 
 	dataChars, err := toChars(combined)
@@ -136,14 +172,14 @@ func ConvertBits(data []byte, fromBits, toBits uint8, pad bool) ([]byte, error)
 	return regrouped, nil
 }
 
-func bech32Checksum(hrp string, data []byte) []byte {
+func bech32Checksum(hrp string, data []byte, encoding Encoding) []byte {
 	integers := make([]int, len(data))
 	for i, b := range data {
 		integers[i] = int(b)
 	}
 	values := append(bech32HrpExpand(hrp), integers...)
 	values = append(values, []int{0, 0, 0, 0, 0, 0}...)
-	polymod := bech32Polymod(values) ^ 1
+	polymod := bech32Polymod(values) ^ encoding.checksumConst()
 	var res []byte
 	for i := 0; i < 6; i++ {
 		res = append(res, byte((polymod>>uint(5*(5-i)))&31))
@@ -177,11 +213,17 @@ func bech32HrpExpand(hrp string) []int {
 	return v
 }
 
-func bech32VerifyChecksum(hrp string, data []byte) bool {
+func bech32VerifyChecksum(hrp string, data []byte) (Encoding, bool) {
 	integers := make([]int, len(data))
 	for i, b := range data {
 		integers[i] = int(b)
 	}
 	concat := append(bech32HrpExpand(hrp), integers...)
-	return bech32Polymod(concat) == 1
+	switch bech32Polymod(concat) {
+	case BECH32.checksumConst():
+		return BECH32, true
+	case BECH32M.checksumConst():
+		return BECH32M, true
+	}
+	return BECH32, false
 }
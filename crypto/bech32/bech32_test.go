@@ -24,3 +24,29 @@ func TestDecode(t *testing.T) {
 	assert.Equal(t, "ckb", hrp)
 	assert.Equal(t, "0004000b1e0f14121b090411031e121f0c08070716071e120f1016101b17080d1c1d0200", hex.EncodeToString(decoded))
 }
+
+func TestBech32m(t *testing.T) {
+	// valid vectors of BIP-350
+	vectors := []string{
+		"a1lqfn3a",
+		"abcdef1l7aum6echk45nj3s0wdvt2fg8x9yrzpqzd3ryx",
+		"split1checkupstagehandshakeupstreamerranterredcaperredlc445v",
+		"?1v759aa",
+	}
+	for _, vector := range vectors {
+		encoding, hrp, decoded, err := DecodeWithEncoding(vector)
+		assert.Nil(t, err, vector)
+		assert.Equal(t, BECH32M, encoding, vector)
+
+		encoded, err := EncodeWithEncoding(hrp, decoded, BECH32M)
+		assert.Nil(t, err)
+		assert.Equal(t, vector, encoded)
+
+		_, _, err = Decode(vector)
+		assert.Error(t, err, vector)
+	}
+
+	encoding, _, _, err := DecodeWithEncoding("ckb1qyqt705jmfy3r7jlvg88k87j0sksmhgduazqrr2qt2")
+	assert.Nil(t, err)
+	assert.Equal(t, BECH32, encoding)
+}
@@ -1,6 +1,9 @@
 package crypto
 
 import (
+	"context"
+	"math/big"
+
 	"github.com/ququzone/ckb-sdk-go/types"
 	"github.com/ququzone/ckb-sdk-go/utils"
 )
@@ -12,8 +15,29 @@ type Key interface {
 	Script(systemScripts *utils.SystemScripts) (*types.Script, error)
 }
 
+// Signer signs with a private key it keeps, it is implemented by local keys, hardware wallets and remote KMS.
+type Signer interface {
+	// PubKey returns the compressed public key
+	PubKey() []byte
+	Script(systemScripts *utils.SystemScripts) (*types.Script, error)
+	// SignMessage returns the 65 bytes recoverable signature of the 32 bytes digest
+	SignMessage(ctx context.Context, digest []byte) ([]byte, error)
+}
+
 func ZeroBytes(bytes []byte) {
 	for i := range bytes {
 		bytes[i] = 0
 	}
 }
+
+// ZeroInt zeroes the words of x in memory, x is 0 after
+func ZeroInt(x *big.Int) {
+	if x == nil {
+		return
+	}
+	words := x.Bits()
+	for i := range words {
+		words[i] = 0
+	}
+	x.SetInt64(0)
+}
@@ -0,0 +1,175 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto/secp256k1"
+
+	"github.com/ququzone/ckb-sdk-go/crypto"
+	"github.com/ququzone/ckb-sdk-go/crypto/blake2b"
+	ckbsecp256k1 "github.com/ququzone/ckb-sdk-go/crypto/secp256k1"
+	"github.com/ququzone/ckb-sdk-go/types"
+	"github.com/ququzone/ckb-sdk-go/utils"
+)
+
+var ErrSignatureMismatch = errors.New("signature is not made by signer public key")
+
+type pubKeyResponse struct {
+	PubKey hexutil.Bytes `json:"pubkey"`
+}
+
+type signRequest struct {
+	Digest hexutil.Bytes `json:"digest"`
+}
+
+type signResponse struct {
+	Signature hexutil.Bytes `json:"signature"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Signer is a crypto.Signer whose key is kept by a remote service, the service speaks the protocol of NewHandler:
+//
+//	GET  {URL}/pubkey                       -> {"pubkey": "0x..."}
+//	POST {URL}/sign {"digest": "0x..."}      -> {"signature": "0x..."}
+type Signer struct {
+	URL    string
+	Client *http.Client
+	pubKey []byte
+}
+
+// NewSigner fetches public key of the remote signer, http.DefaultClient is used when client is nil
+func NewSigner(ctx context.Context, url string, client *http.Client) (*Signer, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	s := &Signer{
+		URL:    strings.TrimSuffix(url, "/"),
+		Client: client,
+	}
+	var result pubKeyResponse
+	if err := s.call(ctx, http.MethodGet, "/pubkey", nil, &result); err != nil {
+		return nil, fmt.Errorf("get public key error: %v", err)
+	}
+	if len(result.PubKey) != 33 {
+		return nil, errors.New("invalid remote public key")
+	}
+	if x, _ := secp256k1.DecompressPubkey(result.PubKey); x == nil {
+		return nil, errors.New("invalid remote public key")
+	}
+	s.pubKey = result.PubKey
+	return s, nil
+}
+
+func (s *Signer) PubKey() []byte {
+	return s.pubKey
+}
+
+func (s *Signer) Script(systemScripts *utils.SystemScripts) (*types.Script, error) {
+	args, err := blake2b.Blake160(s.pubKey)
+	if err != nil {
+		return nil, err
+	}
+	return &types.Script{
+		CodeHash: systemScripts.SecpSingleSigCell.CellHash,
+		HashType: types.HashTypeType,
+		Args:     args,
+	}, nil
+}
+
+// SignMessage asks the remote service to sign digest, the signature is checked against the public key
+func (s *Signer) SignMessage(ctx context.Context, digest []byte) ([]byte, error) {
+	var result signResponse
+	if err := s.call(ctx, http.MethodPost, "/sign", &signRequest{Digest: digest}, &result); err != nil {
+		return nil, fmt.Errorf("remote sign error: %v", err)
+	}
+	if !ckbsecp256k1.Verify(s.pubKey, digest, result.Signature) {
+		return nil, ErrSignatureMismatch
+	}
+	return result.Signature, nil
+}
+
+func (s *Signer) call(ctx context.Context, method string, path string, request interface{}, response interface{}) error {
+	var body []byte
+	if request != nil {
+		var err error
+		body, err = json.Marshal(request)
+		if err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequest(method, s.URL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var e errorResponse
+		if json.Unmarshal(data, &e) == nil && e.Error != "" {
+			return fmt.Errorf("%s: %s", resp.Status, e.Error)
+		}
+		return errors.New(resp.Status)
+	}
+	return json.Unmarshal(data, response)
+}
+
+// NewHandler serves signer over HTTP for Signer, it is a local stand-in of a KMS for tests and development.
+func NewHandler(signer crypto.Signer) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/pubkey", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeJSON(w, http.StatusMethodNotAllowed, &errorResponse{Error: "method not allowed"})
+			return
+		}
+		writeJSON(w, http.StatusOK, &pubKeyResponse{PubKey: signer.PubKey()})
+	})
+	mux.HandleFunc("/sign", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSON(w, http.StatusMethodNotAllowed, &errorResponse{Error: "method not allowed"})
+			return
+		}
+		var req signRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, &errorResponse{Error: err.Error()})
+			return
+		}
+		if len(req.Digest) != 32 {
+			writeJSON(w, http.StatusBadRequest, &errorResponse{Error: "digest must be 32 bytes"})
+			return
+		}
+		signature, err := signer.SignMessage(r.Context(), req.Digest)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, &errorResponse{Error: err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, &signResponse{Signature: signature})
+	})
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
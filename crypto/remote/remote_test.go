@@ -0,0 +1,77 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ququzone/ckb-sdk-go/crypto/secp256k1"
+	"github.com/ququzone/ckb-sdk-go/transaction"
+	"github.com/ququzone/ckb-sdk-go/types"
+	"github.com/ququzone/ckb-sdk-go/utils"
+)
+
+// wrongSigner claims the public key of one key but signs with another
+type wrongSigner struct {
+	*secp256k1.Secp256k1Key
+	other *secp256k1.Secp256k1Key
+}
+
+func (s *wrongSigner) SignMessage(ctx context.Context, digest []byte) ([]byte, error) {
+	return s.other.Sign(digest)
+}
+
+func TestSigner(t *testing.T) {
+	key, err := secp256k1.HexToKey("d00c06bfd800d27397002dca6fb0993d5ba6399b4238b2f29ee9deb97593d2bc")
+	assert.Nil(t, err)
+	server := httptest.NewServer(NewHandler(key))
+	defer server.Close()
+
+	ctx := context.Background()
+	signer, err := NewSigner(ctx, server.URL, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, key.PubKey(), signer.PubKey())
+
+	scripts := &utils.SystemScripts{
+		SecpSingleSigCell: &utils.SystemScriptCell{
+			CellHash: types.HexToHash(transaction.SECP256K1_BLAKE160_SIGHASH_ALL_TYPE_HASH),
+		},
+	}
+	script, err := signer.Script(scripts)
+	assert.Nil(t, err)
+	assert.Equal(t, common.FromHex("0xc8328aabcd9b9e8e64fbc566c4385c3bdeb219d7"), script.Args)
+
+	newTx := func() *types.Transaction {
+		return &types.Transaction{
+			Inputs: []*types.CellInput{
+				{PreviousOutput: &types.OutPoint{TxHash: types.HexToHash("0x0a"), Index: 0}},
+			},
+			Outputs: []*types.CellOutput{
+				{Capacity: 10000000000, Lock: script},
+			},
+			OutputsData: [][]byte{{}},
+			Witnesses:   [][]byte{transaction.EmptyWitnessArgPlaceholder},
+		}
+	}
+	remoteTx := newTx()
+	assert.Nil(t, transaction.SingleSignTransactionWithSigner(ctx, remoteTx, []int{0}, transaction.EmptyWitnessArg, signer))
+	localTx := newTx()
+	assert.Nil(t, transaction.SingleSignTransaction(localTx, []int{0}, transaction.EmptyWitnessArg, key))
+	assert.Equal(t, localTx.Witnesses, remoteTx.Witnesses)
+
+	_, err = signer.SignMessage(ctx, []byte{1, 2, 3})
+	assert.NotNil(t, err)
+
+	other, err := secp256k1.RandomNew()
+	assert.Nil(t, err)
+	wrong := httptest.NewServer(NewHandler(&wrongSigner{Secp256k1Key: key, other: other}))
+	defer wrong.Close()
+	signer, err = NewSigner(ctx, wrong.URL, nil)
+	assert.Nil(t, err)
+	_, err = signer.SignMessage(ctx, make([]byte, 32))
+	assert.True(t, errors.Is(err, ErrSignatureMismatch))
+}
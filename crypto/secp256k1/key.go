@@ -2,6 +2,7 @@ package secp256k1
 
 import (
 	"bytes"
+	"context"
 	"crypto/ecdsa"
 	"crypto/rand"
 	"encoding/hex"
@@ -31,6 +32,14 @@ func (k *Secp256k1Key) Bytes() []byte {
 	return math.PaddedBigBytes(k.PrivateKey.D, k.PrivateKey.Params().BitSize/8)
 }
 
+// Zero zeroes the private key in memory, the key must not be used after
+func (k *Secp256k1Key) Zero() {
+	if k == nil || k.PrivateKey == nil {
+		return
+	}
+	crypto.ZeroInt(k.PrivateKey.D)
+}
+
 func (k *Secp256k1Key) Sign(data []byte) ([]byte, error) {
 	seckey := k.Bytes()
 	defer crypto.ZeroBytes(seckey)
@@ -38,6 +47,11 @@ func (k *Secp256k1Key) Sign(data []byte) ([]byte, error) {
 	return secp256k1.Sign(data, seckey)
 }
 
+// SignMessage implements crypto.Signer
+func (k *Secp256k1Key) SignMessage(ctx context.Context, digest []byte) ([]byte, error) {
+	return k.Sign(digest)
+}
+
 func (k *Secp256k1Key) Script(systemScripts *utils.SystemScripts) (*types.Script, error) {
 	pub := k.PubKey()
 
@@ -0,0 +1,18 @@
+package secp256k1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZero(t *testing.T) {
+	key, err := HexToKey("e79f3207ea4980b7fed79956d5934249ceac4751a4fae01a0f7c4a96884bc4e3")
+	assert.NoError(t, err)
+	words := key.PrivateKey.D.Bits()
+	key.Zero()
+	assert.Equal(t, make([]byte, 32), key.Bytes())
+	for _, word := range words {
+		assert.Zero(t, word)
+	}
+}
@@ -0,0 +1,71 @@
+package secp256k1
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/crypto/secp256k1"
+
+	"github.com/ququzone/ckb-sdk-go/crypto/blake2b"
+)
+
+// MessagePrefix is prepended to a personal message before hashing, it is the scheme of Neuron so a signed
+// message can't be a transaction
+const MessagePrefix = "Nervos Message:"
+
+const signatureLength = 65
+
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// HashMessage returns the ckb blake2b hash of MessagePrefix followed by message
+func HashMessage(message []byte) ([]byte, error) {
+	return blake2b.Blake256(append([]byte(MessagePrefix), message...))
+}
+
+// SignPersonalMessage signs HashMessage(message)
+func (k *Secp256k1Key) SignPersonalMessage(message []byte) ([]byte, error) {
+	digest, err := HashMessage(message)
+	if err != nil {
+		return nil, err
+	}
+	return k.Sign(digest)
+}
+
+// RecoverPubKey returns the compressed public key which made the 65 bytes recoverable signature of digest
+func RecoverPubKey(digest []byte, signature []byte) ([]byte, error) {
+	if len(signature) != signatureLength {
+		return nil, fmt.Errorf("%w: length %d", ErrInvalidSignature, len(signature))
+	}
+	pub, err := secp256k1.RecoverPubkey(digest, signature)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	x, y := secp256k1.S256().Unmarshal(pub)
+	if x == nil {
+		return nil, fmt.Errorf("%w: invalid recovered public key", ErrInvalidSignature)
+	}
+	return secp256k1.CompressPubkey(x, y), nil
+}
+
+// Verify reports whether signature of digest is made by the compressed public key
+func Verify(pubKey []byte, digest []byte, signature []byte) bool {
+	recovered, err := RecoverPubKey(digest, signature)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(recovered, pubKey)
+}
+
+// VerifyLockArgs reports whether signature of digest is made by the key of lock args, the blake160 of public key
+func VerifyLockArgs(lockArgs []byte, digest []byte, signature []byte) bool {
+	recovered, err := RecoverPubKey(digest, signature)
+	if err != nil {
+		return false
+	}
+	hash, err := blake2b.Blake160(recovered)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(hash, lockArgs)
+}
@@ -0,0 +1,44 @@
+package secp256k1
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ququzone/ckb-sdk-go/crypto/blake2b"
+)
+
+func TestRecoverPubKey(t *testing.T) {
+	key, err := HexToKey("e79f3207ea4980b7fed79956d5934249ceac4751a4fae01a0f7c4a96884bc4e3")
+	assert.NoError(t, err)
+	other, err := RandomNew()
+	assert.NoError(t, err)
+
+	digest, err := HashMessage([]byte("login challenge"))
+	assert.NoError(t, err)
+	signature, err := key.SignPersonalMessage([]byte("login challenge"))
+	assert.NoError(t, err)
+
+	pub, err := RecoverPubKey(digest, signature)
+	assert.NoError(t, err)
+	assert.Equal(t, key.PubKey(), pub)
+	assert.True(t, Verify(key.PubKey(), digest, signature))
+	assert.False(t, Verify(other.PubKey(), digest, signature))
+
+	args, _ := blake2b.Blake160(key.PubKey())
+	assert.True(t, VerifyLockArgs(args, digest, signature))
+	otherDigest, _ := HashMessage([]byte("another challenge"))
+	assert.False(t, VerifyLockArgs(args, otherDigest, signature))
+
+	_, err = RecoverPubKey(digest, signature[:64])
+	assert.True(t, errors.Is(err, ErrInvalidSignature))
+	assert.False(t, Verify(key.PubKey(), digest, signature[:64]))
+}
+
+func TestHashMessage(t *testing.T) {
+	digest, err := HashMessage([]byte("hello"))
+	assert.NoError(t, err)
+	expected, _ := blake2b.Blake256([]byte("Nervos Message:hello"))
+	assert.Equal(t, expected, digest)
+}
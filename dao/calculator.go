@@ -0,0 +1,65 @@
+package dao
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ququzone/ckb-sdk-go/types"
+)
+
+// Field is the header dao field, it is four little-endian uint64 packed in 32 bytes
+type Field struct {
+	// C is the total issuance
+	C uint64
+	// AR is the accumulate rate of DAO deposits, 10^16 at genesis
+	AR uint64
+	// S is the total unissued secondary issuance
+	S uint64
+	// U is the total occupied capacity
+	U uint64
+}
+
+func ParseField(dao types.Hash) *Field {
+	data := dao.Bytes()
+	return &Field{
+		C:  binary.LittleEndian.Uint64(data[0:8]),
+		AR: binary.LittleEndian.Uint64(data[8:16]),
+		S:  binary.LittleEndian.Uint64(data[16:24]),
+		U:  binary.LittleEndian.Uint64(data[24:32]),
+	}
+}
+
+func (f *Field) Hash() types.Hash {
+	data := make([]byte, 32)
+	binary.LittleEndian.PutUint64(data[0:8], f.C)
+	binary.LittleEndian.PutUint64(data[8:16], f.AR)
+	binary.LittleEndian.PutUint64(data[16:24], f.S)
+	binary.LittleEndian.PutUint64(data[24:32], f.U)
+	return types.BytesToHash(data)
+}
+
+// CalculateMaximumWithdraw returns capacity of a deposited output released by phase 2, it is the same as the
+// node's calculate_dao_maximum_withdraw: occupied capacity plus the rest scaled by withdraw AR / deposit AR.
+func CalculateMaximumWithdraw(output *types.CellOutput, data []byte, depositHeader, withdrawHeader *types.Header) (uint64, error) {
+	depositAR := ParseField(depositHeader.Dao).AR
+	withdrawAR := ParseField(withdrawHeader.Dao).AR
+	if depositAR == 0 {
+		return 0, errors.New("invalid deposit header dao field")
+	}
+
+	occupied := output.OccupiedCapacity(data)
+	if output.Capacity < occupied {
+		return 0, fmt.Errorf("capacity %d is less than occupied %d", output.Capacity, occupied)
+	}
+	counted := new(big.Int).SetUint64(output.Capacity - occupied)
+	counted.Mul(counted, new(big.Int).SetUint64(withdrawAR))
+	counted.Div(counted, new(big.Int).SetUint64(depositAR))
+	// node truncates u128 to u64 then adds with overflow check
+	withdraw := counted.Uint64()
+	if withdraw+occupied < occupied {
+		return 0, errors.New("maximum withdraw overflow")
+	}
+	return withdraw + occupied, nil
+}
@@ -0,0 +1,76 @@
+package dao
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ququzone/ckb-sdk-go/types"
+)
+
+func TestParseField(t *testing.T) {
+	dao := types.HexToHash("0x8874337e541ea12e0000c16ff286230029bfa3320800000000710b00c0fefe06")
+	field := ParseField(dao)
+	assert.Equal(t, uint64(0x2ea11e547e337488), field.C)
+	assert.Equal(t, uint64(0x002386f26fc10000), field.AR)
+	assert.Equal(t, uint64(0x0000000832a3bf29), field.S)
+	assert.Equal(t, uint64(0x06fefec0000b7100), field.U)
+	assert.Equal(t, dao, field.Hash())
+}
+
+func TestCalculateMaximumWithdraw(t *testing.T) {
+	// vector of node dao calculator tests
+	deposit := &types.Header{Dao: (&Field{AR: 10000000000123456}).Hash()}
+	withdraw := &types.Header{Dao: (&Field{AR: 10000000001123456}).Hash()}
+	output := &types.CellOutput{
+		Capacity: 100000000000000,
+		Lock:     &types.Script{Args: []byte{}},
+	}
+	capacity, err := CalculateMaximumWithdraw(output, common.FromHex("0x01010101010101010101"), deposit, withdraw)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(100000000009999), capacity)
+
+	_, err = CalculateMaximumWithdraw(&types.CellOutput{
+		Capacity: 100,
+		Lock:     &types.Script{Args: []byte{}},
+	}, nil, deposit, withdraw)
+	assert.NotNil(t, err)
+}
+
+func TestAddDaoWithdrawTick(t *testing.T) {
+	deposit := testHeader(1000, &types.EpochParams{Length: 1000, Index: 100, Number: 10}, 10000000000000000)
+	withdraw := testHeader(200000, &types.EpochParams{Length: 1000, Index: 50, Number: 200}, 11000000000000000)
+	// client does not implement CalculateDaoMaximumWithdraw
+	client := &headersClient{tip: withdraw, headers: []*types.Header{deposit}}
+
+	lock := &types.Script{
+		CodeHash: types.HexToHash("0x9bd7e06f3ecf4be0f2fcd2188b23f1b9fcc88e5d4b65a8637b17723bbda3cce8"),
+		HashType: types.HashTypeType,
+		Args:     common.FromHex("0xedcda9513fa030ce4308e29245a22c022d0443bb"),
+	}
+	daoType := &types.Script{
+		CodeHash: types.HexToHash("0x82d76d1b75fe2fd9a27dfbaa65a039221a380d76c926f378d3f81cf3e7e13f2e"),
+		HashType: types.HashTypeType,
+		Args:     []byte{},
+	}
+	w := &WithdrawPhase2{Transaction: &types.Transaction{}}
+	index, witnessArgs, err := w.AddDaoWithdrawTick(client, &types.Cell{
+		BlockHash: deposit.Hash,
+		Capacity:  100000000000,
+		Lock:      lock,
+		Type:      daoType,
+		OutPoint:  &types.OutPoint{TxHash: types.HexToHash("0x0a"), Index: 0},
+	}, &types.Cell{
+		BlockHash: withdraw.Hash,
+		Capacity:  100000000000,
+		Lock:      lock,
+		Type:      daoType,
+		OutPoint:  &types.OutPoint{TxHash: types.HexToHash("0x0b"), Index: 0},
+	}, 1000)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, index)
+	assert.Equal(t, types.SerializeUint64(0), witnessArgs.InputType)
+	assert.Equal(t, uint64(108980000000-1000), w.Transaction.Outputs[0].Capacity)
+	assert.Equal(t, (&types.EpochParams{Length: 1000, Index: 100, Number: 370}).Uint64(), w.Transaction.Inputs[0].Since)
+}
@@ -46,7 +46,7 @@ func (d *Deposit) AddDaoOutput(scripts *utils.SystemScripts, lock *types.Script,
 	if d.Transaction == nil {
 		return errors.New("must init transaction first")
 	}
-	d.Transaction.Outputs = append(d.Transaction.Outputs, &types.CellOutput{
+	output := &types.CellOutput{
 		Capacity: amount,
 		Lock:     lock,
 		Type: &types.Script{
@@ -54,8 +54,13 @@ func (d *Deposit) AddDaoOutput(scripts *utils.SystemScripts, lock *types.Script,
 			HashType: types.HashTypeType,
 			Args:     []byte{},
 		},
-	})
-	d.Transaction.OutputsData = append(d.Transaction.OutputsData, make([]byte, 8))
+	}
+	data := make([]byte, 8)
+	if err := output.CheckCapacity(data); err != nil {
+		return err
+	}
+	d.Transaction.Outputs = append(d.Transaction.Outputs, output)
+	d.Transaction.OutputsData = append(d.Transaction.OutputsData, data)
 
 	return nil
 }
@@ -64,10 +69,14 @@ func (d *Deposit) AddOutput(lock *types.Script, amount uint64) error {
 	if d.Transaction == nil {
 		return errors.New("must init transaction first")
 	}
-	d.Transaction.Outputs = append(d.Transaction.Outputs, &types.CellOutput{
+	output := &types.CellOutput{
 		Capacity: amount,
 		Lock:     lock,
-	})
+	}
+	if err := output.CheckCapacity(nil); err != nil {
+		return err
+	}
+	d.Transaction.Outputs = append(d.Transaction.Outputs, output)
 	d.Transaction.OutputsData = append(d.Transaction.OutputsData, []byte{})
 
 	return nil
@@ -0,0 +1,325 @@
+package dao
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ququzone/ckb-sdk-go/crypto"
+	"github.com/ququzone/ckb-sdk-go/rpc"
+	"github.com/ququzone/ckb-sdk-go/transaction"
+	"github.com/ququzone/ckb-sdk-go/types"
+	"github.com/ququzone/ckb-sdk-go/utils"
+)
+
+// lockPeriodEpochs is the number of epochs in a DAO lock period, phase 2 is allowed at the end of a period
+const lockPeriodEpochs = 180
+
+var (
+	// ErrLockNotMatch is returned when a DAO cell is not locked by the lock script of collector
+	ErrLockNotMatch = errors.New("lock of DAO cell doesn't match collector")
+)
+
+type CellStatus string
+
+const (
+	// StatusDeposited cells can be withdrawn by phase 1
+	StatusDeposited CellStatus = "deposited"
+	// StatusWithdrawing cells have finished phase 1 and wait for the end of lock period
+	StatusWithdrawing CellStatus = "withdrawing"
+	// StatusUnlockable cells can be withdrawn by phase 2
+	StatusUnlockable CellStatus = "unlockable"
+)
+
+// Cell is a DAO cell with its state
+type Cell struct {
+	*types.Cell
+	Status        CellStatus
+	DepositHeader *types.Header
+	// WithdrawHeader is the header of phase 1 block, nil for deposited cells
+	WithdrawHeader *types.Header
+	// Compensation is accrued until phase 1 block, or until tip for deposited cells
+	Compensation uint64
+	// UnlockEpoch is the epoch since which phase 2 is allowed, for deposited cells it assumes phase 1 is done at tip
+	UnlockEpoch *types.EpochParams
+}
+
+// MaximumWithdraw is the capacity released by phase 2
+func (c *Cell) MaximumWithdraw() uint64 {
+	return c.Capacity + c.Compensation
+}
+
+// Manager lists DAO cells of a lock and builds batched withdraw transactions
+type Manager struct {
+	Client  rpc.Client
+	Scripts *utils.SystemScripts
+}
+
+func NewManager(client rpc.Client, scripts *utils.SystemScripts) *Manager {
+	return &Manager{
+		Client:  client,
+		Scripts: scripts,
+	}
+}
+
+// Cells returns DAO cells of collector lock, collector is copied with type script and processor replaced
+func (m *Manager) Cells(ctx context.Context, collector *utils.CellCollector) ([]*Cell, error) {
+	c := *collector
+	c.TypeScript = m.typeScript()
+	c.EmptyData = false
+	c.Processor = utils.NewCapacityCellProcessor(0)
+	result, err := c.CollectWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("collect cell error: %v", err)
+	}
+
+	tip, err := m.Client.GetTipHeader(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get tip header error: %v", err)
+	}
+	tipEpoch := types.ParseEpoch(tip.Epoch)
+
+	cells := make([]*Cell, 0, len(result.Cells))
+	for _, cell := range result.Cells {
+		data, err := m.cellData(ctx, cell)
+		if err != nil {
+			return nil, err
+		}
+		if len(data) != 8 {
+			continue
+		}
+
+		daoCell := &Cell{Cell: cell}
+		header, err := m.Client.GetHeader(ctx, cell.BlockHash)
+		if err != nil {
+			return nil, fmt.Errorf("get block header %s error: %v", cell.BlockHash.String(), err)
+		}
+		depositNumber := binary.LittleEndian.Uint64(data)
+		if depositNumber == 0 {
+			daoCell.Status = StatusDeposited
+			daoCell.DepositHeader = header
+			daoCell.Compensation, err = compensation(cell, data, header, tip)
+			daoCell.UnlockEpoch = minimalSince(header, tip)
+		} else {
+			daoCell.Status = StatusWithdrawing
+			daoCell.WithdrawHeader = header
+			daoCell.DepositHeader, err = m.Client.GetHeaderByNumber(ctx, depositNumber)
+			if err != nil {
+				return nil, fmt.Errorf("get block header of %d error: %v", depositNumber, err)
+			}
+			daoCell.Compensation, err = compensation(cell, data, daoCell.DepositHeader, header)
+			daoCell.UnlockEpoch = minimalSince(daoCell.DepositHeader, header)
+			if tipEpoch.Cmp(daoCell.UnlockEpoch) >= 0 {
+				daoCell.Status = StatusUnlockable
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		cells = append(cells, daoCell)
+	}
+	return cells, nil
+}
+
+// Withdraw builds phase 1 of deposited cells, collector must collect cells of the lock of DAO cells to pay fee,
+// cells locked by other scripts are rejected by ErrLockNotMatch
+func (m *Manager) Withdraw(cells []*Cell, collector *utils.CellCollector, feeRate uint64) (*Batch, error) {
+	if len(cells) == 0 {
+		return nil, errors.New("no DAO cells")
+	}
+	tx := m.newTransaction()
+	var inputCapacity uint64
+	for _, cell := range cells {
+		if cell.Status != StatusDeposited {
+			return nil, fmt.Errorf("cell %s:%d is %s", cell.OutPoint.TxHash.String(), cell.OutPoint.Index, cell.Status)
+		}
+		if !collector.LockScript.Equals(cell.Lock) {
+			return nil, fmt.Errorf("%w: cell %s:%d", ErrLockNotMatch, cell.OutPoint.TxHash.String(), cell.OutPoint.Index)
+		}
+		tx.HeaderDeps = appendHeaderDep(tx.HeaderDeps, cell.BlockHash)
+		tx.Inputs = append(tx.Inputs, &types.CellInput{
+			Since: 0,
+			PreviousOutput: &types.OutPoint{
+				TxHash: cell.OutPoint.TxHash,
+				Index:  cell.OutPoint.Index,
+			},
+		})
+		tx.Witnesses = append(tx.Witnesses, []byte{})
+		tx.Outputs = append(tx.Outputs, &types.CellOutput{
+			Capacity: cell.Capacity,
+			Lock:     cell.Lock,
+			Type:     cell.Type,
+		})
+		tx.OutputsData = append(tx.OutputsData, types.SerializeUint64(cell.DepositHeader.Number))
+		inputCapacity += cell.Capacity
+	}
+	tx.Witnesses[0] = transaction.EmptyWitnessArgPlaceholder
+	return m.build(tx, transaction.EmptyWitnessArg, inputCapacity, collector, feeRate)
+}
+
+// Unlock builds phase 2 of unlockable cells, the released capacity goes to collector lock after fee,
+// cells locked by other scripts are rejected by ErrLockNotMatch
+func (m *Manager) Unlock(cells []*Cell, collector *utils.CellCollector, feeRate uint64) (*Batch, error) {
+	if len(cells) == 0 {
+		return nil, errors.New("no DAO cells")
+	}
+	tx := m.newTransaction()
+	var witnessArgs *types.WitnessArgs
+	var inputCapacity uint64
+	for _, cell := range cells {
+		if cell.Status != StatusUnlockable {
+			return nil, fmt.Errorf("cell %s:%d is %s", cell.OutPoint.TxHash.String(), cell.OutPoint.Index, cell.Status)
+		}
+		if !collector.LockScript.Equals(cell.Lock) {
+			return nil, fmt.Errorf("%w: cell %s:%d", ErrLockNotMatch, cell.OutPoint.TxHash.String(), cell.OutPoint.Index)
+		}
+		tx.HeaderDeps = appendHeaderDep(tx.HeaderDeps, cell.DepositHeader.Hash)
+		depositIndex := headerDepIndex(tx.HeaderDeps, cell.DepositHeader.Hash)
+		tx.HeaderDeps = appendHeaderDep(tx.HeaderDeps, cell.BlockHash)
+		since, err := types.NewAbsoluteEpochSince(cell.UnlockEpoch)
+		if err != nil {
+			return nil, err
+		}
+		tx.Inputs = append(tx.Inputs, &types.CellInput{
+			Since: since.Uint64(),
+			PreviousOutput: &types.OutPoint{
+				TxHash: cell.OutPoint.TxHash,
+				Index:  cell.OutPoint.Index,
+			},
+		})
+
+		// every DAO input needs the deposit header dep index, only the first holds the signature
+		wa := &types.WitnessArgs{
+			InputType: types.SerializeUint64(uint64(depositIndex)),
+		}
+		if witnessArgs == nil {
+			wa.Lock = make([]byte, 65)
+			witnessArgs = wa
+		}
+		witness, err := wa.Serialize()
+		if err != nil {
+			return nil, err
+		}
+		tx.Witnesses = append(tx.Witnesses, witness)
+		inputCapacity += cell.MaximumWithdraw()
+	}
+	return m.build(tx, witnessArgs, inputCapacity, collector, feeRate)
+}
+
+// build pays fee by transaction.Builder, DAO inputs and collected inputs are signed as one group
+func (m *Manager) build(tx *types.Transaction, witnessArgs *types.WitnessArgs, inputCapacity uint64, collector *utils.CellCollector, feeRate uint64) (*Batch, error) {
+	group := make([]int, len(tx.Inputs))
+	for i := range group {
+		group[i] = i
+	}
+
+	builder := transaction.NewBuilder(tx, collector, collector.LockScript, feeRate)
+	builder.InputCapacity = inputCapacity
+	collected, _, err := builder.Build()
+	if err != nil {
+		return nil, err
+	}
+	if len(collected) > 0 {
+		tx.Witnesses[collected[0]] = []byte{}
+		group = append(group, collected...)
+	}
+
+	return &Batch{
+		Transaction: tx,
+		group:       group,
+		witnessArgs: witnessArgs,
+	}, nil
+}
+
+func (m *Manager) newTransaction() *types.Transaction {
+	return &types.Transaction{
+		Version:    0,
+		HeaderDeps: []types.Hash{},
+		CellDeps: []*types.CellDep{
+			{
+				OutPoint: m.Scripts.SecpSingleSigCell.OutPoint,
+				DepType:  types.DepTypeDepGroup,
+			},
+			{
+				OutPoint: m.Scripts.DaoCell.OutPoint,
+				DepType:  types.DepTypeCode,
+			},
+		},
+	}
+}
+
+func (m *Manager) typeScript() *types.Script {
+	return &types.Script{
+		CodeHash: m.Scripts.DaoCell.CellHash,
+		HashType: types.HashTypeType,
+		Args:     []byte{},
+	}
+}
+
+func (m *Manager) cellData(ctx context.Context, cell *types.Cell) ([]byte, error) {
+	if cell.Data != nil {
+		return cell.Data, nil
+	}
+	info, err := m.Client.GetLiveCell(ctx, cell.OutPoint, true)
+	if err != nil {
+		return nil, fmt.Errorf("get live cell error: %v", err)
+	}
+	if info.Cell == nil || info.Cell.Data == nil {
+		return nil, fmt.Errorf("cell %s:%d is not live", cell.OutPoint.TxHash.String(), cell.OutPoint.Index)
+	}
+	return info.Cell.Data.Content, nil
+}
+
+// Batch is a built DAO transaction of single-sig lock cells
+type Batch struct {
+	Transaction *types.Transaction
+	group       []int
+	witnessArgs *types.WitnessArgs
+}
+
+func (b *Batch) Sign(key crypto.Key) (*types.Transaction, error) {
+	err := transaction.SingleSignTransaction(b.Transaction, b.group, b.witnessArgs, key)
+	if err != nil {
+		return nil, fmt.Errorf("sign transaction error: %v", err)
+	}
+	return b.Transaction, nil
+}
+
+// compensation returns interest accrued from deposit header to withdraw header
+func compensation(cell *types.Cell, data []byte, depositHeader, withdrawHeader *types.Header) (uint64, error) {
+	withdraw, err := CalculateMaximumWithdraw(&types.CellOutput{
+		Capacity: cell.Capacity,
+		Lock:     cell.Lock,
+		Type:     cell.Type,
+	}, data, depositHeader, withdrawHeader)
+	if err != nil {
+		return 0, err
+	}
+	if withdraw < cell.Capacity {
+		return 0, errors.New("invalid withdraw header dao field")
+	}
+	return withdraw - cell.Capacity, nil
+}
+
+// minimalSince returns the epoch when a cell withdrawn at withdrawHeader is unlockable,
+// it is the end of the lock period covering the withdraw epoch.
+func minimalSince(depositHeader, withdrawHeader *types.Header) *types.EpochParams {
+	return types.NextEpochOfPeriod(types.ParseEpoch(depositHeader.Epoch), lockPeriodEpochs, types.ParseEpoch(withdrawHeader.Epoch))
+}
+
+func appendHeaderDep(deps []types.Hash, hash types.Hash) []types.Hash {
+	if headerDepIndex(deps, hash) >= 0 {
+		return deps
+	}
+	return append(deps, hash)
+}
+
+func headerDepIndex(deps []types.Hash, hash types.Hash) int {
+	for i, dep := range deps {
+		if dep == hash {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,165 @@
+package dao
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ququzone/ckb-sdk-go/crypto/secp256k1"
+	"github.com/ququzone/ckb-sdk-go/rpc"
+	"github.com/ququzone/ckb-sdk-go/transaction"
+	"github.com/ququzone/ckb-sdk-go/types"
+	"github.com/ququzone/ckb-sdk-go/utils"
+)
+
+type headersClient struct {
+	rpc.Client
+	tip     *types.Header
+	headers []*types.Header
+}
+
+func (c *headersClient) GetTipHeader(ctx context.Context) (*types.Header, error) {
+	return c.tip, nil
+}
+
+func (c *headersClient) GetHeader(ctx context.Context, hash types.Hash) (*types.Header, error) {
+	for _, header := range append(c.headers, c.tip) {
+		if header.Hash == hash {
+			return header, nil
+		}
+	}
+	return nil, rpc.NotFound
+}
+
+func (c *headersClient) GetHeaderByNumber(ctx context.Context, number uint64) (*types.Header, error) {
+	for _, header := range append(c.headers, c.tip) {
+		if header.Number == number {
+			return header, nil
+		}
+	}
+	return nil, rpc.NotFound
+}
+
+func testHeader(number uint64, epoch *types.EpochParams, ar uint64) *types.Header {
+	dao := make([]byte, 32)
+	binary.LittleEndian.PutUint64(dao[8:16], ar)
+	return &types.Header{
+		Number: number,
+		Hash:   types.BytesToHash(types.SerializeUint64(number)),
+		Epoch:  epoch.Length<<40 | epoch.Index<<24 | epoch.Number,
+		Dao:    types.BytesToHash(dao),
+	}
+}
+
+func TestManager(t *testing.T) {
+	scripts := &utils.SystemScripts{
+		SecpSingleSigCell: &utils.SystemScriptCell{
+			CellHash: types.HexToHash(transaction.SECP256K1_BLAKE160_SIGHASH_ALL_TYPE_HASH),
+			OutPoint: &types.OutPoint{TxHash: types.HexToHash("0x01"), Index: 0},
+		},
+		DaoCell: &utils.SystemScriptCell{
+			CellHash: types.HexToHash("0x82d76d1b75fe2fd9a27dfbaa65a039221a380d76c926f378d3f81cf3e7e13f2e"),
+			OutPoint: &types.OutPoint{TxHash: types.HexToHash("0x02"), Index: 2},
+		},
+	}
+	lock := &types.Script{
+		CodeHash: types.HexToHash(transaction.SECP256K1_BLAKE160_SIGHASH_ALL_TYPE_HASH),
+		HashType: types.HashTypeType,
+		Args:     common.FromHex("0xedcda9513fa030ce4308e29245a22c022d0443bb"),
+	}
+	daoType := &types.Script{
+		CodeHash: scripts.DaoCell.CellHash,
+		HashType: types.HashTypeType,
+		Args:     []byte{},
+	}
+
+	deposit := testHeader(1000, &types.EpochParams{Length: 1000, Index: 100, Number: 10}, 10000000000000000)
+	withdraw := testHeader(200000, &types.EpochParams{Length: 1000, Index: 50, Number: 200}, 11000000000000000)
+	tip := testHeader(400000, &types.EpochParams{Length: 1000, Index: 0, Number: 371}, 12000000000000000)
+	client := &headersClient{tip: tip, headers: []*types.Header{deposit, withdraw}}
+
+	collector := utils.NewCellCollector(client, lock, nil)
+	collector.Provider = utils.NewMemoryCellProvider(
+		&types.Cell{
+			BlockHash:     deposit.Hash,
+			Capacity:      100000000000,
+			Lock:          lock,
+			Type:          daoType,
+			OutPoint:      &types.OutPoint{TxHash: types.HexToHash("0x0a"), Index: 0},
+			OutputDataLen: 8,
+			Data:          make([]byte, 8),
+		},
+		&types.Cell{
+			BlockHash:     withdraw.Hash,
+			Capacity:      100000000000,
+			Lock:          lock,
+			Type:          daoType,
+			OutPoint:      &types.OutPoint{TxHash: types.HexToHash("0x0b"), Index: 0},
+			OutputDataLen: 8,
+			Data:          types.SerializeUint64(deposit.Number),
+		},
+		&types.Cell{
+			BlockHash: tip.Hash,
+			Capacity:  50000000000,
+			Lock:      lock,
+			OutPoint:  &types.OutPoint{TxHash: types.HexToHash("0x0c"), Index: 0},
+		},
+	)
+
+	manager := NewManager(client, scripts)
+	cells, err := manager.Cells(context.Background(), collector)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(cells))
+
+	// occupied capacity is 102 CKB, 898 CKB accrues interest by AR
+	assert.Equal(t, StatusDeposited, cells[0].Status)
+	assert.Equal(t, uint64(17960000000), cells[0].Compensation)
+	assert.Equal(t, &types.EpochParams{Length: 1000, Index: 100, Number: 550}, cells[0].UnlockEpoch)
+
+	assert.Equal(t, StatusUnlockable, cells[1].Status)
+	assert.Equal(t, uint64(8980000000), cells[1].Compensation)
+	assert.Equal(t, uint64(108980000000), cells[1].MaximumWithdraw())
+	assert.Equal(t, &types.EpochParams{Length: 1000, Index: 100, Number: 370}, cells[1].UnlockEpoch)
+
+	_, err = manager.Unlock(cells, collector, 1000)
+	assert.NotNil(t, err)
+
+	other := *cells[0].Cell
+	other.Lock = &types.Script{
+		CodeHash: lock.CodeHash,
+		HashType: lock.HashType,
+		Args:     common.FromHex("0x36c329ed630d6ce750712a477543672adab57f4c"),
+	}
+	_, err = manager.Withdraw([]*Cell{cells[0], {Cell: &other, Status: StatusDeposited}}, collector, 1000)
+	assert.True(t, errors.Is(err, ErrLockNotMatch))
+	_, err = manager.Unlock([]*Cell{{Cell: &other, Status: StatusUnlockable}}, collector, 1000)
+	assert.True(t, errors.Is(err, ErrLockNotMatch))
+
+	batch, err := manager.Withdraw(cells[:1], collector, 1000)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(batch.Transaction.Inputs))
+	assert.Equal(t, types.SerializeUint64(deposit.Number), batch.Transaction.OutputsData[0])
+	assert.Equal(t, []int{0, 1}, batch.group)
+
+	batch, err = manager.Unlock(cells[1:], collector, 1000)
+	assert.Nil(t, err)
+	tx := batch.Transaction
+	assert.Equal(t, 1, len(tx.Inputs))
+	assert.Equal(t, cells[1].UnlockEpoch.Uint64(), tx.Inputs[0].Since)
+	assert.Equal(t, []types.Hash{deposit.Hash, withdraw.Hash}, tx.HeaderDeps)
+	assert.Equal(t, 1, len(tx.Outputs))
+	assert.True(t, tx.Outputs[0].Capacity < cells[1].MaximumWithdraw())
+
+	key, err := secp256k1.HexToKey("d00c06bfd800d27397002dca6fb0993d5ba6399b4238b2f29ee9deb97593d2bc")
+	assert.Nil(t, err)
+	_, err = batch.Sign(key)
+	assert.Nil(t, err)
+	witness := &types.WitnessArgs{}
+	assert.Nil(t, witness.Deserialize(tx.Witnesses[0]))
+	assert.Equal(t, 65, len(witness.Lock))
+	assert.Equal(t, types.SerializeUint64(0), witness.InputType)
+}
@@ -73,10 +73,14 @@ func (w *WithdrawPhase1) AddOutput(lock *types.Script, amount uint64) error {
 	if w.Transaction == nil {
 		return errors.New("must init transaction first")
 	}
-	w.Transaction.Outputs = append(w.Transaction.Outputs, &types.CellOutput{
+	output := &types.CellOutput{
 		Capacity: amount,
 		Lock:     lock,
-	})
+	}
+	if err := output.CheckCapacity(nil); err != nil {
+		return err
+	}
+	w.Transaction.Outputs = append(w.Transaction.Outputs, output)
 	w.Transaction.OutputsData = append(w.Transaction.OutputsData, []byte{})
 
 	return nil
@@ -128,29 +132,16 @@ func (w *WithdrawPhase2) AddDaoWithdrawTick(client rpc.Client, depositCell *type
 		return 0, nil, fmt.Errorf("get block header from address %s error: %v", withdrawCell.BlockHash.String(), err)
 	}
 
-	withdrawEpoch := types.ParseEpoch(headerWithdraw.Epoch)
-	depositEpoch := types.ParseEpoch(headerDeposit.Epoch)
-
-	withdrawFraction := withdrawEpoch.Index * depositEpoch.Length
-	depositFraction := depositEpoch.Index * withdrawEpoch.Length
-	depositedEpochs := withdrawEpoch.Number - depositEpoch.Number
-	if withdrawFraction > depositFraction {
-		depositedEpochs += 1
-	}
-	lockEpochs := (depositedEpochs + 179) / 180 * 180
-	minimalSinceEpochNumber := depositEpoch.Number + lockEpochs
-	minimalSinceEpochIndex := depositEpoch.Index
-	minimalSinceEpochLength := depositEpoch.Length
-
-	minimalSince := &types.EpochParams{
-		Length: minimalSinceEpochLength,
-		Index:  minimalSinceEpochIndex,
-		Number: minimalSinceEpochNumber,
+	since, err := types.NewAbsoluteEpochSince(minimalSince(headerDeposit, headerWithdraw))
+	if err != nil {
+		return 0, nil, err
 	}
-	capacity, err := client.CalculateDaoMaximumWithdraw(context.Background(), &types.OutPoint{
-		TxHash: depositCell.OutPoint.TxHash,
-		Index:  depositCell.OutPoint.Index,
-	}, headerWithdraw.Hash)
+	// deposit data is 8 bytes as the withdrawing data
+	capacity, err := CalculateMaximumWithdraw(&types.CellOutput{
+		Capacity: withdrawCell.Capacity,
+		Lock:     withdrawCell.Lock,
+		Type:     withdrawCell.Type,
+	}, make([]byte, 8), headerDeposit, headerWithdraw)
 	if err != nil {
 		return 0, nil, fmt.Errorf("calculate Dao maximum withdraw error: %v", err)
 	}
@@ -158,22 +149,26 @@ func (w *WithdrawPhase2) AddDaoWithdrawTick(client rpc.Client, depositCell *type
 	if fee > capacity {
 		return 0, nil, fmt.Errorf("the fee(%d) is too big that withdraw(%d) is not enough", fee, capacity)
 	}
+	output := &types.CellOutput{
+		Capacity: capacity - fee,
+		Lock:     withdrawCell.Lock,
+	}
+	if err := output.CheckCapacity(nil); err != nil {
+		return 0, nil, err
+	}
 
 	w.Transaction.HeaderDeps = append(w.Transaction.HeaderDeps, depositCell.BlockHash)
 	w.Transaction.HeaderDeps = append(w.Transaction.HeaderDeps, withdrawCell.BlockHash)
 
 	w.Transaction.Inputs = append(w.Transaction.Inputs, &types.CellInput{
-		Since: minimalSince.Uint64(),
+		Since: since.Uint64(),
 		PreviousOutput: &types.OutPoint{
 			TxHash: withdrawCell.OutPoint.TxHash,
 			Index:  withdrawCell.OutPoint.Index,
 		},
 	})
 	w.Transaction.Witnesses = append(w.Transaction.Witnesses, []byte{})
-	w.Transaction.Outputs = append(w.Transaction.Outputs, &types.CellOutput{
-		Capacity: capacity - fee,
-		Lock:     withdrawCell.Lock,
-	})
+	w.Transaction.Outputs = append(w.Transaction.Outputs, output)
 	w.Transaction.OutputsData = append(w.Transaction.OutputsData, []byte{})
 
 	return len(w.Transaction.Inputs) - 1, &types.WitnessArgs{
@@ -187,10 +182,14 @@ func (w *WithdrawPhase2) AddOutput(lock *types.Script, amount uint64) error {
 	if w.Transaction == nil {
 		return errors.New("must init transaction first")
 	}
-	w.Transaction.Outputs = append(w.Transaction.Outputs, &types.CellOutput{
+	output := &types.CellOutput{
 		Capacity: amount,
 		Lock:     lock,
-	})
+	}
+	if err := output.CheckCapacity(nil); err != nil {
+		return err
+	}
+	w.Transaction.Outputs = append(w.Transaction.Outputs, output)
 	w.Transaction.OutputsData = append(w.Transaction.OutputsData, []byte{})
 
 	return nil
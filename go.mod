@@ -5,6 +5,9 @@ go 1.12
 require (
 	github.com/ethereum/go-ethereum v1.9.14
 	github.com/golang/mock v1.3.1
+	github.com/gorilla/websocket v1.4.1-0.20190629185528-ae1634f6a989
 	github.com/minio/blake2b-simd v0.0.0-20160723061019-3f5f724cb5b1
 	github.com/stretchr/testify v1.4.0
+	github.com/tyler-smith/go-bip39 v1.0.2
+	golang.org/x/crypto v0.0.0-20200311171314-f7b00557c8c4
 )
@@ -0,0 +1,278 @@
+package hdwallet
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto/secp256k1"
+	"golang.org/x/crypto/ripemd160"
+
+	"github.com/ququzone/ckb-sdk-go/crypto"
+	ckbsecp256k1 "github.com/ququzone/ckb-sdk-go/crypto/secp256k1"
+)
+
+// HardenedKeyStart is the index of the first hardened child key
+const HardenedKeyStart uint32 = 0x80000000
+
+const (
+	serializedKeyLength = 78
+	minSeedBytes        = 16
+	maxSeedBytes        = 64
+)
+
+var (
+	// version bytes of xprv and xpub
+	privateVersion = []byte{0x04, 0x88, 0xad, 0xe4}
+	publicVersion  = []byte{0x04, 0x88, 0xb2, 0x1e}
+
+	masterSecret = []byte("Bitcoin seed")
+)
+
+var (
+	ErrInvalidSeed              = errors.New("seed length must be between 128 and 512 bits")
+	ErrInvalidChild             = errors.New("derived key is invalid, use the next index")
+	ErrDeriveHardenedFromPublic = errors.New("cannot derive a hardened key from a public key")
+	ErrNotPrivate               = errors.New("extended key is not a private key")
+	ErrInvalidExtendedKey       = errors.New("invalid extended key")
+)
+
+// ExtendedKey is a BIP32 extended private or public key
+type ExtendedKey struct {
+	// key is the 32 bytes private key or the 33 bytes compressed public key
+	key         []byte
+	chainCode   []byte
+	depth       uint8
+	parentFP    []byte
+	childNumber uint32
+	private     bool
+}
+
+// NewMaster returns the master key of seed, a BIP39 seed is 64 bytes
+func NewMaster(seed []byte) (*ExtendedKey, error) {
+	if len(seed) < minSeedBytes || len(seed) > maxSeedBytes {
+		return nil, ErrInvalidSeed
+	}
+	mac := hmac.New(sha512.New, masterSecret)
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+
+	k := new(big.Int).SetBytes(sum[:32])
+	if k.Sign() == 0 || k.Cmp(secp256k1.S256().N) >= 0 {
+		return nil, ErrInvalidSeed
+	}
+	return &ExtendedKey{
+		key:       sum[:32],
+		chainCode: sum[32:],
+		parentFP:  []byte{0, 0, 0, 0},
+		private:   true,
+	}, nil
+}
+
+func (k *ExtendedKey) IsPrivate() bool {
+	return k.private
+}
+
+func (k *ExtendedKey) Depth() uint8 {
+	return k.depth
+}
+
+func (k *ExtendedKey) ChildNumber() uint32 {
+	return k.childNumber
+}
+
+func (k *ExtendedKey) ChainCode() []byte {
+	return append([]byte{}, k.chainCode...)
+}
+
+// PubKey returns the compressed public key
+func (k *ExtendedKey) PubKey() []byte {
+	if !k.private {
+		return append([]byte{}, k.key...)
+	}
+	x, y := secp256k1.S256().ScalarBaseMult(k.key)
+	return secp256k1.CompressPubkey(x, y)
+}
+
+// Fingerprint is the first 4 bytes of hash160 of public key
+func (k *ExtendedKey) Fingerprint() []byte {
+	sha := sha256.Sum256(k.PubKey())
+	hash := ripemd160.New()
+	hash.Write(sha[:])
+	return hash.Sum(nil)[:4]
+}
+
+// Child derives the child key of index, index from HardenedKeyStart are hardened keys which a public key can't derive
+func (k *ExtendedKey) Child(index uint32) (*ExtendedKey, error) {
+	hardened := index >= HardenedKeyStart
+	if hardened && !k.private {
+		return nil, ErrDeriveHardenedFromPublic
+	}
+
+	var data []byte
+	if hardened {
+		data = append([]byte{0}, k.key...)
+	} else {
+		data = k.PubKey()
+	}
+	data = append(data, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(data[len(data)-4:], index)
+	defer crypto.ZeroBytes(data)
+
+	mac := hmac.New(sha512.New, k.chainCode)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+	il := new(big.Int).SetBytes(sum[:32])
+	curve := secp256k1.S256()
+	if il.Cmp(curve.N) >= 0 {
+		return nil, ErrInvalidChild
+	}
+
+	var key []byte
+	if k.private {
+		child := new(big.Int).Add(il, new(big.Int).SetBytes(k.key))
+		child.Mod(child, curve.N)
+		if child.Sign() == 0 {
+			return nil, ErrInvalidChild
+		}
+		key = math.PaddedBigBytes(child, 32)
+	} else {
+		ilx, ily := curve.ScalarBaseMult(sum[:32])
+		px, py := secp256k1.DecompressPubkey(k.key)
+		if px == nil {
+			return nil, ErrInvalidExtendedKey
+		}
+		x, y := curve.Add(ilx, ily, px, py)
+		if x.Sign() == 0 && y.Sign() == 0 {
+			return nil, ErrInvalidChild
+		}
+		key = secp256k1.CompressPubkey(x, y)
+	}
+	crypto.ZeroBytes(sum[:32])
+
+	return &ExtendedKey{
+		key:         key,
+		chainCode:   sum[32:],
+		depth:       k.depth + 1,
+		parentFP:    k.Fingerprint(),
+		childNumber: index,
+		private:     k.private,
+	}, nil
+}
+
+// Derive derives the key of path relative to k
+func (k *ExtendedKey) Derive(path DerivationPath) (*ExtendedKey, error) {
+	key := k
+	for _, index := range path {
+		var err error
+		key, err = key.Child(index)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+// Neuter returns the extended public key, it derives the same public keys for non-hardened indexes
+func (k *ExtendedKey) Neuter() *ExtendedKey {
+	if !k.private {
+		return k
+	}
+	return &ExtendedKey{
+		key:         k.PubKey(),
+		chainCode:   k.ChainCode(),
+		depth:       k.depth,
+		parentFP:    k.parentFP,
+		childNumber: k.childNumber,
+	}
+}
+
+// Secp256k1Key returns the private key
+func (k *ExtendedKey) Secp256k1Key() (*ckbsecp256k1.Secp256k1Key, error) {
+	if !k.private {
+		return nil, ErrNotPrivate
+	}
+	return ckbsecp256k1.ToKey(k.key)
+}
+
+// Zero zeroes the private key of k, it must not be used after
+func (k *ExtendedKey) Zero() {
+	crypto.ZeroBytes(k.key)
+	crypto.ZeroBytes(k.chainCode)
+}
+
+// String returns the base58 serialization, xprv or xpub
+func (k *ExtendedKey) String() string {
+	data := make([]byte, 0, serializedKeyLength+4)
+	if k.private {
+		data = append(data, privateVersion...)
+	} else {
+		data = append(data, publicVersion...)
+	}
+	data = append(data, k.depth)
+	data = append(data, k.parentFP...)
+	data = append(data, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(data[len(data)-4:], k.childNumber)
+	data = append(data, k.chainCode...)
+	if k.private {
+		data = append(data, 0)
+	}
+	data = append(data, k.key...)
+	data = append(data, checksum(data)...)
+	return base58Encode(data)
+}
+
+// ParseExtendedKey parses a base58 serialized xprv or xpub
+func ParseExtendedKey(s string) (*ExtendedKey, error) {
+	data, err := base58Decode(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidExtendedKey, err)
+	}
+	if len(data) != serializedKeyLength+4 {
+		return nil, fmt.Errorf("%w: length %d", ErrInvalidExtendedKey, len(data))
+	}
+	payload := data[:serializedKeyLength]
+	if !bytes.Equal(checksum(payload), data[serializedKeyLength:]) {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrInvalidExtendedKey)
+	}
+
+	k := &ExtendedKey{
+		depth:       payload[4],
+		parentFP:    payload[5:9],
+		childNumber: binary.BigEndian.Uint32(payload[9:13]),
+		chainCode:   payload[13:45],
+	}
+	keyData := payload[45:]
+	switch {
+	case bytes.Equal(payload[:4], privateVersion):
+		if keyData[0] != 0 {
+			return nil, fmt.Errorf("%w: private key prefix", ErrInvalidExtendedKey)
+		}
+		d := new(big.Int).SetBytes(keyData[1:])
+		if d.Sign() == 0 || d.Cmp(secp256k1.S256().N) >= 0 {
+			return nil, fmt.Errorf("%w: private key out of range", ErrInvalidExtendedKey)
+		}
+		k.key = keyData[1:]
+		k.private = true
+	case bytes.Equal(payload[:4], publicVersion):
+		if x, _ := secp256k1.DecompressPubkey(keyData); x == nil {
+			return nil, fmt.Errorf("%w: public key", ErrInvalidExtendedKey)
+		}
+		k.key = keyData
+	default:
+		return nil, fmt.Errorf("%w: version %x", ErrInvalidExtendedKey, payload[:4])
+	}
+	return k, nil
+}
+
+func checksum(data []byte) []byte {
+	first := sha256.Sum256(data)
+	second := sha256.Sum256(first[:])
+	return second[:4]
+}
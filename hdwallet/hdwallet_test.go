@@ -0,0 +1,176 @@
+package hdwallet
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ququzone/ckb-sdk-go/address"
+	"github.com/ququzone/ckb-sdk-go/crypto/blake2b"
+	"github.com/ququzone/ckb-sdk-go/rpc"
+	"github.com/ququzone/ckb-sdk-go/transaction"
+	"github.com/ququzone/ckb-sdk-go/types"
+	"github.com/ququzone/ckb-sdk-go/utils"
+)
+
+func TestBIP32Vector(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	master, err := NewMaster(seed)
+	assert.NoError(t, err)
+
+	vectors := []struct {
+		path string
+		xprv string
+		xpub string
+	}{
+		{
+			"m",
+			"xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi",
+			"xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8",
+		},
+		{
+			"m/0'",
+			"xprv9uHRZZhk6KAJC1avXpDAp4MDc3sQKNxDiPvvkX8Br5ngLNv1TxvUxt4cV1rGL5hj6KCesnDYUhd7oWgT11eZG7XnxHrnYeSvkzY7d2bhkJ7",
+			"xpub68Gmy5EdvgibQVfPdqkBBCHxA5htiqg55crXYuXoQRKfDBFA1WEjWgP6LHhwBZeNK1VTsfTFUHCdrfp1bgwQ9xv5ski8PX9rL2dZXvgGDnw",
+		},
+		{
+			"m/0'/1",
+			"xprv9wTYmMFdV23N2TdNG573QoEsfRrWKQgWeibmLntzniatZvR9BmLnvSxqu53Kw1UmYPxLgboyZQaXwTCg8MSY3H2EU4pWcQDnRnrVA1xe8fs",
+			"xpub6ASuArnXKPbfEwhqN6e3mwBcDTgzisQN1wXN9BJcM47sSikHjJf3UFHKkNAWbWMiGj7Wf5uMash7SyYq527Hqck2AxYysAA7xmALppuCkwQ",
+		},
+	}
+	for _, v := range vectors {
+		path, err := ParseDerivationPath(v.path)
+		assert.NoError(t, err)
+		key, err := master.Derive(path)
+		assert.NoError(t, err)
+		assert.Equal(t, v.xprv, key.String(), v.path)
+		assert.Equal(t, v.xpub, key.Neuter().String(), v.path)
+
+		parsed, err := ParseExtendedKey(v.xpub)
+		assert.NoError(t, err)
+		assert.Equal(t, v.xpub, parsed.String())
+	}
+
+	xpub, err := ParseExtendedKey(vectors[1].xpub)
+	assert.NoError(t, err)
+	child, err := xpub.Child(1)
+	assert.NoError(t, err)
+	assert.Equal(t, vectors[2].xpub, child.String())
+	_, err = xpub.Child(HardenedKeyStart)
+	assert.True(t, errors.Is(err, ErrDeriveHardenedFromPublic))
+}
+
+func TestDerivationPath(t *testing.T) {
+	path, err := ParseDerivationPath("m/44'/309'/0'/1/5")
+	assert.NoError(t, err)
+	assert.Equal(t, CKBPath(0, ChangeChange, 5), path)
+	assert.Equal(t, "m/44'/309'/0'/1/5", path.String())
+	assert.Equal(t, "m/44'/309'/0'", DefaultAccountPath.String())
+
+	_, err = ParseDerivationPath("44'/309'")
+	assert.True(t, errors.Is(err, ErrInvalidPath))
+	_, err = ParseDerivationPath("m/2147483648")
+	assert.True(t, errors.Is(err, ErrInvalidPath))
+}
+
+func TestWallet(t *testing.T) {
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	seed, err := NewSeed(mnemonic, "TREZOR")
+	assert.NoError(t, err)
+	assert.Equal(t, "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04", hex.EncodeToString(seed))
+	_, err = NewSeed("abandon abandon abandon", "")
+	assert.Error(t, err)
+
+	wallet, err := NewWallet(mnemonic, "")
+	assert.NoError(t, err)
+	master, _ := NewMaster(mustSeed(t, mnemonic))
+	expected, err := master.Derive(CKBPath(0, ChangeReceive, 3))
+	assert.NoError(t, err)
+	key, err := wallet.Key(ChangeReceive, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, expected.PubKey(), key.PubKey())
+
+	watchOnly, err := NewWatchOnly(wallet.WatchOnly().AccountPublicKey())
+	assert.NoError(t, err)
+	pub, err := watchOnly.PubKey(ChangeReceive, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, key.PubKey(), pub)
+
+	scripts := testScripts()
+	addr, err := watchOnly.Address(address.Testnet, scripts, ChangeReceive, 3)
+	assert.NoError(t, err)
+	script, err := key.Script(scripts)
+	assert.NoError(t, err)
+	expectedAddress, err := address.Generate(address.Testnet, script)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedAddress, addr)
+
+	mnemonic, err = NewMnemonic(256)
+	assert.NoError(t, err)
+	_, err = NewWallet(mnemonic, "passphrase")
+	assert.NoError(t, err)
+}
+
+type usedClient struct {
+	rpc.Client
+	used map[types.Hash]bool
+}
+
+func (c *usedClient) GetTransactionsByLockHash(ctx context.Context, lockHash types.Hash, page uint, per uint, reverseOrder bool) ([]*types.CellTransaction, error) {
+	if c.used[lockHash] {
+		return []*types.CellTransaction{{}}, nil
+	}
+	return nil, nil
+}
+
+func TestScan(t *testing.T) {
+	wallet, err := NewWallet("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "")
+	assert.NoError(t, err)
+	watchOnly := wallet.WatchOnly()
+	scripts := testScripts()
+
+	client := &usedClient{used: make(map[types.Hash]bool)}
+	markUsed := func(change uint32, index uint32) {
+		script, err := watchOnly.LockScript(scripts, change, index)
+		assert.NoError(t, err)
+		hash, err := script.Hash()
+		assert.NoError(t, err)
+		client.used[hash] = true
+	}
+	markUsed(ChangeReceive, 0)
+	markUsed(ChangeReceive, 4)
+	// beyond the gap limit of 5 after index 4
+	markUsed(ChangeReceive, 10)
+	markUsed(ChangeChange, 1)
+
+	result, err := watchOnly.Scan(context.Background(), client, scripts, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(result.Receive))
+	assert.Equal(t, uint32(4), result.Receive[1].Index)
+	assert.Equal(t, uint32(5), result.NextReceive)
+	assert.Equal(t, 1, len(result.Change))
+	assert.Equal(t, uint32(2), result.NextChange)
+
+	key, err := wallet.Key(ChangeChange, 1)
+	assert.NoError(t, err)
+	args, _ := blake2b.Blake160(key.PubKey())
+	assert.Equal(t, args, result.Change[0].Script.Args)
+}
+
+func mustSeed(t *testing.T, mnemonic string) []byte {
+	seed, err := NewSeed(mnemonic, "")
+	assert.NoError(t, err)
+	return seed
+}
+
+func testScripts() *utils.SystemScripts {
+	return &utils.SystemScripts{
+		SecpSingleSigCell: &utils.SystemScriptCell{
+			CellHash: types.HexToHash(transaction.SECP256K1_BLAKE160_SIGHASH_ALL_TYPE_HASH),
+		},
+	}
+}
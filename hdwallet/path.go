@@ -0,0 +1,69 @@
+package hdwallet
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// CKBCoinType is the SLIP-0044 coin type of CKB
+	CKBCoinType uint32 = 309
+
+	// ChangeReceive and ChangeChange are the change levels of BIP44 for receive and change addresses
+	ChangeReceive uint32 = 0
+	ChangeChange  uint32 = 1
+)
+
+var ErrInvalidPath = errors.New("invalid derivation path")
+
+// DerivationPath is a BIP32 path of child indexes, hardened indexes are from HardenedKeyStart
+type DerivationPath []uint32
+
+// DefaultAccountPath is m/44'/309'/0', the account path used by ckb-cli and Neuron
+var DefaultAccountPath = DerivationPath{44 + HardenedKeyStart, CKBCoinType + HardenedKeyStart, HardenedKeyStart}
+
+// ParseDerivationPath parses path like m/44'/309'/0'/0/1, both ' and h mark a hardened index
+func ParseDerivationPath(path string) (DerivationPath, error) {
+	components := strings.Split(strings.TrimSpace(path), "/")
+	if len(components) == 0 || components[0] != "m" {
+		return nil, fmt.Errorf("%w: %s must start with m", ErrInvalidPath, path)
+	}
+	result := make(DerivationPath, 0, len(components)-1)
+	for _, component := range components[1:] {
+		hardened := strings.HasSuffix(component, "'") || strings.HasSuffix(component, "h")
+		if hardened {
+			component = component[:len(component)-1]
+		}
+		index, err := strconv.ParseUint(component, 10, 32)
+		if err != nil || uint32(index) >= HardenedKeyStart {
+			return nil, fmt.Errorf("%w: index %s of %s", ErrInvalidPath, component, path)
+		}
+		if hardened {
+			index += uint64(HardenedKeyStart)
+		}
+		result = append(result, uint32(index))
+	}
+	return result, nil
+}
+
+// CKBPath returns m/44'/309'/account'/change/index
+func CKBPath(account uint32, change uint32, index uint32) DerivationPath {
+	return DerivationPath{44 + HardenedKeyStart, CKBCoinType + HardenedKeyStart, account + HardenedKeyStart, change, index}
+}
+
+func (p DerivationPath) String() string {
+	var sb strings.Builder
+	sb.WriteString("m")
+	for _, index := range p {
+		sb.WriteString("/")
+		if index >= HardenedKeyStart {
+			sb.WriteString(strconv.FormatUint(uint64(index-HardenedKeyStart), 10))
+			sb.WriteString("'")
+		} else {
+			sb.WriteString(strconv.FormatUint(uint64(index), 10))
+		}
+	}
+	return sb.String()
+}
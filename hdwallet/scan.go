@@ -0,0 +1,83 @@
+package hdwallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ququzone/ckb-sdk-go/rpc"
+	"github.com/ququzone/ckb-sdk-go/types"
+	"github.com/ququzone/ckb-sdk-go/utils"
+)
+
+// DefaultGapLimit is the BIP44 address gap limit
+const DefaultGapLimit uint32 = 20
+
+// DerivedLock is a lock script derived at change and index
+type DerivedLock struct {
+	Change uint32
+	Index  uint32
+	Script *types.Script
+}
+
+// ScanResult lists used locks of receive and change chains, Next* is the index after the last used lock
+type ScanResult struct {
+	Receive     []*DerivedLock
+	Change      []*DerivedLock
+	NextReceive uint32
+	NextChange  uint32
+}
+
+// Scan discovers used receive and change locks, a chain is scanned until gapLimit consecutive unused locks.
+// A lock is used when it has transactions in the node indexer, lock hashes must be indexed by IndexLockHash,
+// so scanning a restored wallet needs the derived lock hashes indexed from the genesis block first.
+func (w *WatchOnly) Scan(ctx context.Context, client rpc.Client, systemScripts *utils.SystemScripts, gapLimit uint32) (*ScanResult, error) {
+	if gapLimit == 0 {
+		gapLimit = DefaultGapLimit
+	}
+	receive, nextReceive, err := w.scanChain(ctx, client, systemScripts, ChangeReceive, gapLimit)
+	if err != nil {
+		return nil, err
+	}
+	change, nextChange, err := w.scanChain(ctx, client, systemScripts, ChangeChange, gapLimit)
+	if err != nil {
+		return nil, err
+	}
+	return &ScanResult{
+		Receive:     receive,
+		Change:      change,
+		NextReceive: nextReceive,
+		NextChange:  nextChange,
+	}, nil
+}
+
+func (w *WatchOnly) scanChain(ctx context.Context, client rpc.Client, systemScripts *utils.SystemScripts, change uint32, gapLimit uint32) ([]*DerivedLock, uint32, error) {
+	var used []*DerivedLock
+	var next uint32
+	for index, gap := uint32(0), uint32(0); gap < gapLimit; index++ {
+		script, err := w.LockScript(systemScripts, change, index)
+		if errors.Is(err, ErrInvalidChild) {
+			// skip the index as BIP32 suggests, the probability is lower than 1 in 2^127
+			continue
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		lockHash, err := script.Hash()
+		if err != nil {
+			return nil, 0, err
+		}
+		txs, err := client.GetTransactionsByLockHash(ctx, lockHash, 0, 1, false)
+		if err != nil {
+			return nil, 0, fmt.Errorf("get transactions of lock %s error: %v", lockHash.String(), err)
+		}
+		if len(txs) == 0 {
+			gap++
+			continue
+		}
+		gap = 0
+		used = append(used, &DerivedLock{Change: change, Index: index, Script: script})
+		next = index + 1
+	}
+	return used, next, nil
+}
@@ -0,0 +1,147 @@
+package hdwallet
+
+import (
+	"fmt"
+
+	"github.com/tyler-smith/go-bip39"
+
+	"github.com/ququzone/ckb-sdk-go/address"
+	"github.com/ququzone/ckb-sdk-go/crypto"
+	"github.com/ququzone/ckb-sdk-go/crypto/blake2b"
+	"github.com/ququzone/ckb-sdk-go/crypto/secp256k1"
+	"github.com/ququzone/ckb-sdk-go/types"
+	"github.com/ququzone/ckb-sdk-go/utils"
+)
+
+// NewMnemonic returns a BIP39 english mnemonic of bits entropy, 128 bits for 12 words and 256 bits for 24 words
+func NewMnemonic(bits int) (string, error) {
+	entropy, err := bip39.NewEntropy(bits)
+	if err != nil {
+		return "", err
+	}
+	defer crypto.ZeroBytes(entropy)
+	return bip39.NewMnemonic(entropy)
+}
+
+// NewSeed returns the BIP39 seed of mnemonic with an optional passphrase, the mnemonic checksum is verified
+func NewSeed(mnemonic string, passphrase string) ([]byte, error) {
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mnemonic: %v", err)
+	}
+	return seed, nil
+}
+
+// Wallet derives keys of a BIP44 account, m/44'/309'/0'/change/index by default
+type Wallet struct {
+	account *ExtendedKey
+}
+
+func NewWallet(mnemonic string, passphrase string) (*Wallet, error) {
+	seed, err := NewSeed(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	defer crypto.ZeroBytes(seed)
+	return NewWalletFromSeed(seed)
+}
+
+func NewWalletFromSeed(seed []byte) (*Wallet, error) {
+	master, err := NewMaster(seed)
+	if err != nil {
+		return nil, err
+	}
+	defer master.Zero()
+	account, err := master.Derive(DefaultAccountPath)
+	if err != nil {
+		return nil, err
+	}
+	return &Wallet{account: account}, nil
+}
+
+// NewWalletFromAccountKey uses an extended private key of account level, such as m/44'/309'/1'
+func NewWalletFromAccountKey(account *ExtendedKey) (*Wallet, error) {
+	if !account.IsPrivate() {
+		return nil, ErrNotPrivate
+	}
+	return &Wallet{account: account}, nil
+}
+
+// Key derives the key of change and index, change is ChangeReceive or ChangeChange
+func (w *Wallet) Key(change uint32, index uint32) (*secp256k1.Secp256k1Key, error) {
+	key, err := w.account.Derive(DerivationPath{change, index})
+	if err != nil {
+		return nil, err
+	}
+	defer key.Zero()
+	return key.Secp256k1Key()
+}
+
+// AccountKey returns the extended private key of account
+func (w *Wallet) AccountKey() *ExtendedKey {
+	return w.account
+}
+
+// WatchOnly returns the watch-only wallet of the account, it derives the same addresses without private keys
+func (w *Wallet) WatchOnly() *WatchOnly {
+	return &WatchOnly{account: w.account.Neuter()}
+}
+
+// Zero zeroes the account private key, the wallet must not be used after
+func (w *Wallet) Zero() {
+	w.account.Zero()
+}
+
+// WatchOnly derives public keys and addresses from an account extended public key
+type WatchOnly struct {
+	account *ExtendedKey
+}
+
+// NewWatchOnly parses the xpub of account level exported by Wallet.WatchOnly().AccountPublicKey()
+func NewWatchOnly(xpub string) (*WatchOnly, error) {
+	key, err := ParseExtendedKey(xpub)
+	if err != nil {
+		return nil, err
+	}
+	return &WatchOnly{account: key.Neuter()}, nil
+}
+
+// AccountPublicKey returns the serialized extended public key of account
+func (w *WatchOnly) AccountPublicKey() string {
+	return w.account.String()
+}
+
+// PubKey derives the compressed public key of change and index
+func (w *WatchOnly) PubKey(change uint32, index uint32) ([]byte, error) {
+	key, err := w.account.Derive(DerivationPath{change, index})
+	if err != nil {
+		return nil, err
+	}
+	return key.PubKey(), nil
+}
+
+// LockScript derives the secp256k1 single sign lock of change and index
+func (w *WatchOnly) LockScript(systemScripts *utils.SystemScripts, change uint32, index uint32) (*types.Script, error) {
+	pub, err := w.PubKey(change, index)
+	if err != nil {
+		return nil, err
+	}
+	args, err := blake2b.Blake160(pub)
+	if err != nil {
+		return nil, err
+	}
+	return &types.Script{
+		CodeHash: systemScripts.SecpSingleSigCell.CellHash,
+		HashType: types.HashTypeType,
+		Args:     args,
+	}, nil
+}
+
+// Address derives the short address of change and index
+func (w *WatchOnly) Address(mode address.Mode, systemScripts *utils.SystemScripts, change uint32, index uint32) (string, error) {
+	script, err := w.LockScript(systemScripts, change, index)
+	if err != nil {
+		return "", err
+	}
+	return address.Generate(mode, script)
+}
@@ -0,0 +1,121 @@
+package indexer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ququzone/ckb-sdk-go/types"
+	"github.com/ququzone/ckb-sdk-go/utils"
+)
+
+type request struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+const lockJSON = `{"code_hash":"0x9bd7e06f3ecf4be0f2fcd2188b23f1b9fcc88e5d4b65a8637b17723bbda3cce8","hash_type":"type","args":"0x01"}`
+
+// newServer serves 3 cells by pages of 2, cursors are page numbers
+func newServer(t *testing.T, requests *[]request) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req request
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&req))
+		*requests = append(*requests, req)
+
+		var result string
+		switch req.Method {
+		case "get_tip":
+			result = `{"block_hash":"0x0000000000000000000000000000000000000000000000000000000000000001","block_number":"0x10"}`
+		case "get_cells":
+			cell := func(i int) string {
+				return fmt.Sprintf(`{"block_number":"0x%x","out_point":{"tx_hash":"0x0000000000000000000000000000000000000000000000000000000000000002","index":"0x%x"},"output":{"capacity":"0x100","lock":%s,"type":null},"output_data":"0x","tx_index":"0x1"}`, i, i, lockJSON)
+			}
+			if len(req.Params) == 3 {
+				result = fmt.Sprintf(`{"last_cursor":"0x01","objects":[%s,%s]}`, cell(0), cell(1))
+			} else {
+				result = fmt.Sprintf(`{"last_cursor":"0x02","objects":[%s]}`, cell(2))
+			}
+		case "get_transactions":
+			result = `{"last_cursor":"0x01","objects":[{"block_number":"0x5","io_index":"0x0","io_type":"output","tx_hash":"0x0000000000000000000000000000000000000000000000000000000000000002","tx_index":"0x1"}]}`
+		case "get_cells_capacity":
+			result = `{"capacity":"0x300","block_hash":"0x0000000000000000000000000000000000000000000000000000000000000001","block_number":"0x10"}`
+		}
+		_, _ = fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":%s}`, req.ID, result)
+	}))
+}
+
+func testKey() *SearchKey {
+	return &SearchKey{
+		Script: &types.Script{
+			CodeHash: types.HexToHash("0x9bd7e06f3ecf4be0f2fcd2188b23f1b9fcc88e5d4b65a8637b17723bbda3cce8"),
+			HashType: types.HashTypeType,
+			Args:     []byte{0x01},
+		},
+		ScriptType: ScriptTypeLock,
+	}
+}
+
+func TestClient(t *testing.T) {
+	var requests []request
+	server := newServer(t, &requests)
+	defer server.Close()
+
+	client, err := Dial(server.URL)
+	assert.Nil(t, err)
+	defer client.Close()
+
+	tip, err := client.GetTip(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(16), tip.BlockNumber)
+
+	key := testKey()
+	key.Filter = &CellsFilter{
+		OutputDataLenRange: &[2]uint64{0, 1},
+		BlockRange:         &[2]uint64{0, 100},
+	}
+	cells, err := client.GetCells(context.Background(), key, SearchOrderAsc, 2, "")
+	assert.Nil(t, err)
+	assert.Equal(t, "0x01", cells.LastCursor)
+	assert.Equal(t, 2, len(cells.Objects))
+	assert.Equal(t, uint64(256), cells.Objects[1].Output.Capacity)
+	assert.Equal(t, uint(1), cells.Objects[1].OutPoint.Index)
+	assert.JSONEq(t, `{"script":`+lockJSON+`,"script_type":"lock","filter":{"output_data_len_range":["0x0","0x1"],"block_range":["0x0","0x64"]}}`, string(requests[1].Params[0]))
+	assert.Equal(t, `"asc"`, string(requests[1].Params[1]))
+	assert.Equal(t, `"0x2"`, string(requests[1].Params[2]))
+
+	txs, err := client.GetTransactions(context.Background(), testKey(), SearchOrderDesc, 10, "0x01")
+	assert.Nil(t, err)
+	assert.Equal(t, IoTypeOutput, txs.Objects[0].IoType)
+	assert.Equal(t, `"0x01"`, string(requests[2].Params[3]))
+
+	capacity, err := client.GetCellsCapacity(context.Background(), testKey())
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0x300), capacity.Capacity)
+}
+
+func TestCellProvider(t *testing.T) {
+	var requests []request
+	server := newServer(t, &requests)
+	defer server.Close()
+
+	client, err := Dial(server.URL)
+	assert.Nil(t, err)
+	defer client.Close()
+
+	provider := NewCellProvider(client, nil)
+	provider.PageSize = 2
+	collector := utils.NewCellCollector(nil, testKey().Script, utils.NewCapacityCellProcessor(0))
+	collector.Provider = provider
+	result, err := collector.Collect()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(result.Cells))
+	assert.Equal(t, uint64(0x300), result.Capacity)
+	assert.Equal(t, 2, len(requests))
+}
@@ -0,0 +1,128 @@
+package indexer
+
+import (
+	"context"
+)
+
+// CellIterator walks all pages of GetCells.
+//
+//	iterator := indexer.NewCellIterator(ctx, client, searchKey, indexer.SearchOrderAsc, 100)
+//	for iterator.Next() {
+//		cell := iterator.Cell()
+//	}
+//	if err := iterator.Err(); err != nil {
+//	}
+type CellIterator struct {
+	ctx      context.Context
+	client   Client
+	key      *SearchKey
+	order    SearchOrder
+	pageSize uint64
+
+	cursor string
+	cells  []*LiveCell
+	index  int
+	done   bool
+	err    error
+}
+
+func NewCellIterator(ctx context.Context, client Client, key *SearchKey, order SearchOrder, pageSize uint64) *CellIterator {
+	return &CellIterator{
+		ctx:      ctx,
+		client:   client,
+		key:      key,
+		order:    order,
+		pageSize: pageSize,
+		index:    -1,
+	}
+}
+
+// Next advances to the next cell, it returns false when all cells are visited or an error occurs
+func (it *CellIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	it.index++
+	for it.index >= len(it.cells) {
+		if it.done {
+			return false
+		}
+		page, err := it.client.GetCells(it.ctx, it.key, it.order, it.pageSize, it.cursor)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.cells = page.Objects
+		it.index = 0
+		it.cursor = page.LastCursor
+		it.done = uint64(len(page.Objects)) < it.pageSize
+	}
+	return true
+}
+
+// Cell returns the current cell
+func (it *CellIterator) Cell() *LiveCell {
+	return it.cells[it.index]
+}
+
+func (it *CellIterator) Err() error {
+	return it.err
+}
+
+// TransactionIterator walks all pages of GetTransactions, it is used like CellIterator.
+type TransactionIterator struct {
+	ctx      context.Context
+	client   Client
+	key      *SearchKey
+	order    SearchOrder
+	pageSize uint64
+
+	cursor string
+	txs    []*Transaction
+	index  int
+	done   bool
+	err    error
+}
+
+func NewTransactionIterator(ctx context.Context, client Client, key *SearchKey, order SearchOrder, pageSize uint64) *TransactionIterator {
+	return &TransactionIterator{
+		ctx:      ctx,
+		client:   client,
+		key:      key,
+		order:    order,
+		pageSize: pageSize,
+		index:    -1,
+	}
+}
+
+// Next advances to the next transaction, it returns false when all transactions are visited or an error occurs
+func (it *TransactionIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	it.index++
+	for it.index >= len(it.txs) {
+		if it.done {
+			return false
+		}
+		page, err := it.client.GetTransactions(it.ctx, it.key, it.order, it.pageSize, it.cursor)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.txs = page.Objects
+		it.index = 0
+		it.cursor = page.LastCursor
+		it.done = uint64(len(page.Objects)) < it.pageSize
+	}
+	return true
+}
+
+// Transaction returns the current transaction
+func (it *TransactionIterator) Transaction() *Transaction {
+	return it.txs[it.index]
+}
+
+func (it *TransactionIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,70 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ququzone/ckb-sdk-go/rpc"
+	"github.com/ququzone/ckb-sdk-go/types"
+)
+
+// CellProvider is a utils.CellProvider backed by ckb-indexer.
+// Node is optional, when set it is used to fill BlockHash of cells which is required by DAO withdraw.
+type CellProvider struct {
+	Client   Client
+	Node     rpc.Client
+	PageSize uint64
+}
+
+func NewCellProvider(client Client, node rpc.Client) *CellProvider {
+	return &CellProvider{
+		Client:   client,
+		Node:     node,
+		PageSize: 100,
+	}
+}
+
+func (p *CellProvider) Cells(ctx context.Context, lock *types.Script, fn func(*types.Cell) (bool, error)) error {
+	iterator := NewCellIterator(ctx, p.Client, &SearchKey{
+		Script:     lock,
+		ScriptType: ScriptTypeLock,
+	}, SearchOrderAsc, p.PageSize)
+
+	blockHashes := make(map[uint64]types.Hash)
+	for iterator.Next() {
+		cell := iterator.Cell()
+		// args are matched as prefix by indexer
+		if !lock.Equals(cell.Output.Lock) {
+			continue
+		}
+
+		var blockHash types.Hash
+		if p.Node != nil {
+			var ok bool
+			blockHash, ok = blockHashes[cell.BlockNumber]
+			if !ok {
+				hash, err := p.Node.GetBlockHash(ctx, cell.BlockNumber)
+				if err != nil {
+					return fmt.Errorf("get block hash of %d error: %v", cell.BlockNumber, err)
+				}
+				blockHash = *hash
+				blockHashes[cell.BlockNumber] = blockHash
+			}
+		}
+
+		stop, err := fn(&types.Cell{
+			BlockHash:     blockHash,
+			Capacity:      cell.Output.Capacity,
+			Lock:          cell.Output.Lock,
+			OutPoint:      cell.OutPoint,
+			Type:          cell.Output.Type,
+			Cellbase:      cell.TxIndex == 0,
+			OutputDataLen: uint64(len(cell.OutputData)),
+			Data:          cell.OutputData,
+		})
+		if err != nil || stop {
+			return err
+		}
+	}
+	return iterator.Err()
+}
@@ -0,0 +1,228 @@
+package indexer
+
+import (
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/ququzone/ckb-sdk-go/types"
+)
+
+type ScriptType string
+type SearchOrder string
+type IoType string
+
+const (
+	ScriptTypeLock ScriptType = "lock"
+	ScriptTypeType ScriptType = "type"
+
+	SearchOrderAsc  SearchOrder = "asc"
+	SearchOrderDesc SearchOrder = "desc"
+
+	IoTypeInput  IoType = "input"
+	IoTypeOutput IoType = "output"
+)
+
+// SearchKey selects cells or transactions by Script, the script args are matched as prefix.
+type SearchKey struct {
+	Script     *types.Script
+	ScriptType ScriptType
+	Filter     *CellsFilter
+}
+
+// CellsFilter narrows search results, all ranges are [from, to).
+type CellsFilter struct {
+	// Script is the type script when searching by lock, the lock script when searching by type
+	Script              *types.Script
+	OutputDataLenRange  *[2]uint64
+	OutputCapacityRange *[2]uint64
+	BlockRange          *[2]uint64
+}
+
+type TipHeader struct {
+	BlockHash   types.Hash
+	BlockNumber uint64
+}
+
+type LiveCell struct {
+	BlockNumber uint64
+	OutPoint    *types.OutPoint
+	Output      *types.CellOutput
+	OutputData  []byte
+	TxIndex     uint
+}
+
+type LiveCells struct {
+	LastCursor string
+	Objects    []*LiveCell
+}
+
+type Transaction struct {
+	BlockNumber uint64
+	IoIndex     uint
+	IoType      IoType
+	TxHash      types.Hash
+	TxIndex     uint
+}
+
+type Transactions struct {
+	LastCursor string
+	Objects    []*Transaction
+}
+
+type Capacity struct {
+	Capacity    uint64
+	BlockHash   types.Hash
+	BlockNumber uint64
+}
+
+type script struct {
+	CodeHash types.Hash           `json:"code_hash"`
+	HashType types.ScriptHashType `json:"hash_type"`
+	Args     hexutil.Bytes        `json:"args"`
+}
+
+type searchKey struct {
+	Script     *script      `json:"script"`
+	ScriptType ScriptType   `json:"script_type"`
+	Filter     *cellsFilter `json:"filter,omitempty"`
+}
+
+type cellsFilter struct {
+	Script              *script            `json:"script,omitempty"`
+	OutputDataLenRange  *[2]hexutil.Uint64 `json:"output_data_len_range,omitempty"`
+	OutputCapacityRange *[2]hexutil.Uint64 `json:"output_capacity_range,omitempty"`
+	BlockRange          *[2]hexutil.Uint64 `json:"block_range,omitempty"`
+}
+
+type tipHeader struct {
+	BlockHash   types.Hash     `json:"block_hash"`
+	BlockNumber hexutil.Uint64 `json:"block_number"`
+}
+
+type outPoint struct {
+	TxHash types.Hash   `json:"tx_hash"`
+	Index  hexutil.Uint `json:"index"`
+}
+
+type cellOutput struct {
+	Capacity hexutil.Uint64 `json:"capacity"`
+	Lock     *script        `json:"lock"`
+	Type     *script        `json:"type"`
+}
+
+type liveCell struct {
+	BlockNumber hexutil.Uint64 `json:"block_number"`
+	OutPoint    outPoint       `json:"out_point"`
+	Output      cellOutput     `json:"output"`
+	OutputData  hexutil.Bytes  `json:"output_data"`
+	TxIndex     hexutil.Uint   `json:"tx_index"`
+}
+
+type liveCells struct {
+	LastCursor string      `json:"last_cursor"`
+	Objects    []*liveCell `json:"objects"`
+}
+
+type transaction struct {
+	BlockNumber hexutil.Uint64 `json:"block_number"`
+	IoIndex     hexutil.Uint   `json:"io_index"`
+	IoType      IoType         `json:"io_type"`
+	TxHash      types.Hash     `json:"tx_hash"`
+	TxIndex     hexutil.Uint   `json:"tx_index"`
+}
+
+type transactions struct {
+	LastCursor string         `json:"last_cursor"`
+	Objects    []*transaction `json:"objects"`
+}
+
+type capacity struct {
+	Capacity    hexutil.Uint64 `json:"capacity"`
+	BlockHash   types.Hash     `json:"block_hash"`
+	BlockNumber hexutil.Uint64 `json:"block_number"`
+}
+
+func fromScript(s *types.Script) *script {
+	if s == nil {
+		return nil
+	}
+	return &script{
+		CodeHash: s.CodeHash,
+		HashType: s.HashType,
+		Args:     s.Args,
+	}
+}
+
+func fromRange(r *[2]uint64) *[2]hexutil.Uint64 {
+	if r == nil {
+		return nil
+	}
+	return &[2]hexutil.Uint64{hexutil.Uint64(r[0]), hexutil.Uint64(r[1])}
+}
+
+func fromSearchKey(key *SearchKey) *searchKey {
+	result := &searchKey{
+		Script:     fromScript(key.Script),
+		ScriptType: key.ScriptType,
+	}
+	if key.Filter != nil {
+		result.Filter = &cellsFilter{
+			Script:              fromScript(key.Filter.Script),
+			OutputDataLenRange:  fromRange(key.Filter.OutputDataLenRange),
+			OutputCapacityRange: fromRange(key.Filter.OutputCapacityRange),
+			BlockRange:          fromRange(key.Filter.BlockRange),
+		}
+	}
+	return result
+}
+
+func toScript(s *script) *types.Script {
+	if s == nil {
+		return nil
+	}
+	return &types.Script{
+		CodeHash: s.CodeHash,
+		HashType: s.HashType,
+		Args:     s.Args,
+	}
+}
+
+func toLiveCells(cells liveCells) *LiveCells {
+	result := &LiveCells{
+		LastCursor: cells.LastCursor,
+		Objects:    make([]*LiveCell, len(cells.Objects)),
+	}
+	for i, cell := range cells.Objects {
+		result.Objects[i] = &LiveCell{
+			BlockNumber: uint64(cell.BlockNumber),
+			OutPoint: &types.OutPoint{
+				TxHash: cell.OutPoint.TxHash,
+				Index:  uint(cell.OutPoint.Index),
+			},
+			Output: &types.CellOutput{
+				Capacity: uint64(cell.Output.Capacity),
+				Lock:     toScript(cell.Output.Lock),
+				Type:     toScript(cell.Output.Type),
+			},
+			OutputData: cell.OutputData,
+			TxIndex:    uint(cell.TxIndex),
+		}
+	}
+	return result
+}
+
+func toTransactions(txs transactions) *Transactions {
+	result := &Transactions{
+		LastCursor: txs.LastCursor,
+		Objects:    make([]*Transaction, len(txs.Objects)),
+	}
+	for i, tx := range txs.Objects {
+		result.Objects[i] = &Transaction{
+			BlockNumber: uint64(tx.BlockNumber),
+			IoIndex:     uint(tx.IoIndex),
+			IoType:      tx.IoType,
+			TxHash:      tx.TxHash,
+			TxIndex:     uint(tx.TxIndex),
+		}
+	}
+	return result
+}
@@ -0,0 +1,329 @@
+package keystore
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/crypto/sha3"
+
+	"github.com/ququzone/ckb-sdk-go/crypto"
+	"github.com/ququzone/ckb-sdk-go/crypto/blake2b"
+	"github.com/ququzone/ckb-sdk-go/crypto/secp256k1"
+)
+
+const (
+	// StandardScryptN and StandardScryptP are scrypt parameters of ckb-cli, about 256MB memory and 1s CPU
+	StandardScryptN = 1 << 18
+	StandardScryptP = 1
+
+	// LightScryptN and LightScryptP use about 4MB memory and 100ms CPU
+	LightScryptN = 1 << 12
+	LightScryptP = 6
+
+	// StandardPBKDF2Iterations is the iteration count of hmac-sha256 pbkdf2
+	StandardPBKDF2Iterations = 1 << 18
+
+	version     = 3
+	scryptR     = 8
+	keyLength   = 32
+	cipherName  = "aes-128-ctr"
+	kdfScrypt   = "scrypt"
+	kdfPBKDF2   = "pbkdf2"
+	prfSHA256   = "hmac-sha256"
+	saltLength  = 32
+	secretBytes = 32
+	// ckb-cli stores the 64 bytes master key which is secret key followed by chain code
+	masterKeyBytes = 64
+
+	// bounds of kdf params read from key files, so a crafted file can't exhaust memory or CPU
+	maxDerivedKeyLength = 64
+	maxScryptN          = 1 << 20
+	// maxScryptCost bounds 128 * n * r * p, the bytes scrypt mixes, 1GB is 4 times of the standard params
+	maxScryptCost       = 1 << 30
+	maxPBKDF2Iterations = 1 << 22
+)
+
+var (
+	ErrDecrypt            = errors.New("could not decrypt key with given password")
+	ErrUnsupportedKeyFile = errors.New("unsupported key file")
+)
+
+type keyJSON struct {
+	ID      string     `json:"id"`
+	Version int        `json:"version"`
+	Crypto  cryptoJSON `json:"crypto"`
+	// Hash160 is the lock args of the key in hex without prefix
+	Hash160 string `json:"hash160,omitempty"`
+}
+
+type cryptoJSON struct {
+	Cipher       string                 `json:"cipher"`
+	CipherText   string                 `json:"ciphertext"`
+	CipherParams cipherParamsJSON       `json:"cipherparams"`
+	KDF          string                 `json:"kdf"`
+	KDFParams    map[string]interface{} `json:"kdfparams"`
+	MAC          string                 `json:"mac"`
+}
+
+type cipherParamsJSON struct {
+	IV string `json:"iv"`
+}
+
+// EncryptKey encrypts key with scrypt derived password into Web3 Secret Storage JSON which ckb-cli imports
+func EncryptKey(key *secp256k1.Secp256k1Key, password string, scryptN, scryptP int) ([]byte, error) {
+	salt, err := randomBytes(saltLength)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkScryptParams(scryptN, scryptR, scryptP); err != nil {
+		return nil, err
+	}
+	passwordBytes := []byte(password)
+	defer crypto.ZeroBytes(passwordBytes)
+	derivedKey, err := scrypt.Key(passwordBytes, salt, scryptN, scryptR, scryptP, keyLength)
+	if err != nil {
+		return nil, fmt.Errorf("scrypt error: %v", err)
+	}
+	defer crypto.ZeroBytes(derivedKey)
+
+	return encryptKey(key, derivedKey, kdfScrypt, map[string]interface{}{
+		"n":     scryptN,
+		"r":     scryptR,
+		"p":     scryptP,
+		"dklen": keyLength,
+		"salt":  hex.EncodeToString(salt),
+	})
+}
+
+// EncryptKeyPBKDF2 encrypts key with hmac-sha256 pbkdf2 derived password
+func EncryptKeyPBKDF2(key *secp256k1.Secp256k1Key, password string, iterations int) ([]byte, error) {
+	if iterations <= 0 || iterations > maxPBKDF2Iterations {
+		return nil, fmt.Errorf("%w: pbkdf2 iterations %d", ErrUnsupportedKeyFile, iterations)
+	}
+	salt, err := randomBytes(saltLength)
+	if err != nil {
+		return nil, err
+	}
+	passwordBytes := []byte(password)
+	defer crypto.ZeroBytes(passwordBytes)
+	derivedKey := pbkdf2.Key(passwordBytes, salt, iterations, keyLength, sha256.New)
+	defer crypto.ZeroBytes(derivedKey)
+
+	return encryptKey(key, derivedKey, kdfPBKDF2, map[string]interface{}{
+		"c":     iterations,
+		"prf":   prfSHA256,
+		"dklen": keyLength,
+		"salt":  hex.EncodeToString(salt),
+	})
+}
+
+func encryptKey(key *secp256k1.Secp256k1Key, derivedKey []byte, kdf string, kdfParams map[string]interface{}) ([]byte, error) {
+	iv, err := randomBytes(aes.BlockSize)
+	if err != nil {
+		return nil, err
+	}
+	secret := key.Bytes()
+	defer crypto.ZeroBytes(secret)
+
+	cipherText, err := aesCTR(derivedKey[:16], iv, secret)
+	if err != nil {
+		return nil, err
+	}
+	id, err := newUUID()
+	if err != nil {
+		return nil, err
+	}
+	lockArgs, err := blake2b.Blake160(key.PubKey())
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(&keyJSON{
+		ID:      id,
+		Version: version,
+		Crypto: cryptoJSON{
+			Cipher:       cipherName,
+			CipherText:   hex.EncodeToString(cipherText),
+			CipherParams: cipherParamsJSON{IV: hex.EncodeToString(iv)},
+			KDF:          kdf,
+			KDFParams:    kdfParams,
+			MAC:          hex.EncodeToString(mac(derivedKey, cipherText)),
+		},
+		Hash160: hex.EncodeToString(lockArgs),
+	})
+}
+
+// DecryptKey decrypts a Web3 Secret Storage JSON key, both plain 32 bytes secret keys and 64 bytes ckb-cli
+// master keys are accepted, the chain code of master key is dropped.
+func DecryptKey(data []byte, password string) (*secp256k1.Secp256k1Key, error) {
+	var k keyJSON
+	if err := json.Unmarshal(data, &k); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedKeyFile, err)
+	}
+	if k.Version != version {
+		return nil, fmt.Errorf("%w: version %d", ErrUnsupportedKeyFile, k.Version)
+	}
+	if k.Crypto.Cipher != cipherName {
+		return nil, fmt.Errorf("%w: cipher %s", ErrUnsupportedKeyFile, k.Crypto.Cipher)
+	}
+	cipherText, err := hex.DecodeString(k.Crypto.CipherText)
+	if err != nil {
+		return nil, fmt.Errorf("%w: ciphertext %v", ErrUnsupportedKeyFile, err)
+	}
+	iv, err := hex.DecodeString(k.Crypto.CipherParams.IV)
+	if err != nil || len(iv) != aes.BlockSize {
+		return nil, fmt.Errorf("%w: invalid iv", ErrUnsupportedKeyFile)
+	}
+	expectedMAC, err := hex.DecodeString(k.Crypto.MAC)
+	if err != nil {
+		return nil, fmt.Errorf("%w: mac %v", ErrUnsupportedKeyFile, err)
+	}
+
+	derivedKey, err := deriveKey(k.Crypto.KDF, k.Crypto.KDFParams, password)
+	if err != nil {
+		return nil, err
+	}
+	defer crypto.ZeroBytes(derivedKey)
+
+	if !bytes.Equal(mac(derivedKey, cipherText), expectedMAC) {
+		return nil, ErrDecrypt
+	}
+	plain, err := aesCTR(derivedKey[:16], iv, cipherText)
+	if err != nil {
+		return nil, err
+	}
+	defer crypto.ZeroBytes(plain)
+
+	if len(plain) != secretBytes && len(plain) != masterKeyBytes {
+		return nil, fmt.Errorf("%w: key length %d", ErrUnsupportedKeyFile, len(plain))
+	}
+	return secp256k1.ToKey(plain[:secretBytes])
+}
+
+func deriveKey(kdf string, params map[string]interface{}, password string) ([]byte, error) {
+	salt, err := hex.DecodeString(stringParam(params, "salt"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: salt %v", ErrUnsupportedKeyFile, err)
+	}
+	dkLen, err := intParam(params, "dklen")
+	if err != nil {
+		return nil, err
+	}
+	if dkLen < keyLength || dkLen > maxDerivedKeyLength {
+		return nil, fmt.Errorf("%w: dklen %d", ErrUnsupportedKeyFile, dkLen)
+	}
+
+	passwordBytes := []byte(password)
+	defer crypto.ZeroBytes(passwordBytes)
+	switch kdf {
+	case kdfScrypt:
+		values := make([]int, 3)
+		for i, name := range []string{"n", "r", "p"} {
+			if values[i], err = intParam(params, name); err != nil {
+				return nil, err
+			}
+		}
+		n, r, p := values[0], values[1], values[2]
+		if err := checkScryptParams(n, r, p); err != nil {
+			return nil, err
+		}
+		key, err := scrypt.Key(passwordBytes, salt, n, r, p, dkLen)
+		if err != nil {
+			return nil, fmt.Errorf("%w: scrypt %v", ErrUnsupportedKeyFile, err)
+		}
+		return key, nil
+	case kdfPBKDF2:
+		if prf := stringParam(params, "prf"); prf != prfSHA256 {
+			return nil, fmt.Errorf("%w: prf %s", ErrUnsupportedKeyFile, prf)
+		}
+		c, err := intParam(params, "c")
+		if err != nil {
+			return nil, err
+		}
+		if c <= 0 || c > maxPBKDF2Iterations {
+			return nil, fmt.Errorf("%w: pbkdf2 iterations %d", ErrUnsupportedKeyFile, c)
+		}
+		return pbkdf2.Key(passwordBytes, salt, c, dkLen, sha256.New), nil
+	default:
+		return nil, fmt.Errorf("%w: kdf %s", ErrUnsupportedKeyFile, kdf)
+	}
+}
+
+// checkScryptParams requires n to be a power of two greater than 1 and bounds the memory and CPU cost
+func checkScryptParams(n, r, p int) error {
+	if n <= 1 || n > maxScryptN || n&(n-1) != 0 {
+		return fmt.Errorf("%w: scrypt n %d", ErrUnsupportedKeyFile, n)
+	}
+	if r <= 0 || p <= 0 || r > maxScryptCost/(128*n) || p > maxScryptCost/(128*n*r) {
+		return fmt.Errorf("%w: scrypt n %d r %d p %d", ErrUnsupportedKeyFile, n, r, p)
+	}
+	return nil
+}
+
+// mac is keccak256 of the second half of derived key and cipher text
+func mac(derivedKey []byte, cipherText []byte) []byte {
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write(derivedKey[16:32])
+	hash.Write(cipherText)
+	return hash.Sum(nil)
+}
+
+func aesCTR(key []byte, iv []byte, input []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	output := make([]byte, len(input))
+	cipher.NewCTR(block, iv).XORKeyStream(output, input)
+	return output, nil
+}
+
+// intParam returns the integer param, json numbers are decoded as float64
+func intParam(params map[string]interface{}, name string) (int, error) {
+	switch v := params[name].(type) {
+	case float64:
+		if v != math.Trunc(v) || v < 0 || v > math.MaxInt32 {
+			return 0, fmt.Errorf("%w: %s %v", ErrUnsupportedKeyFile, name, v)
+		}
+		return int(v), nil
+	case int:
+		return v, nil
+	default:
+		return 0, fmt.Errorf("%w: missing %s", ErrUnsupportedKeyFile, name)
+	}
+}
+
+func stringParam(params map[string]interface{}, name string) string {
+	v, _ := params[name].(string)
+	return v
+}
+
+func randomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return nil, fmt.Errorf("read random error: %v", err)
+	}
+	return b, nil
+}
+
+// newUUID returns a random version 4 UUID
+func newUUID() (string, error) {
+	b, err := randomBytes(16)
+	if err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
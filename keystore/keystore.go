@@ -0,0 +1,359 @@
+package keystore
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ququzone/ckb-sdk-go/crypto"
+	"github.com/ququzone/ckb-sdk-go/crypto/blake2b"
+	"github.com/ququzone/ckb-sdk-go/crypto/secp256k1"
+	"github.com/ququzone/ckb-sdk-go/types"
+	"github.com/ququzone/ckb-sdk-go/utils"
+)
+
+var (
+	ErrAccountNotFound = errors.New("account not found")
+	ErrAccountExists   = errors.New("account already exists")
+	ErrLocked          = errors.New("account is locked")
+)
+
+// Account is a key file in keystore directory
+type Account struct {
+	// LockArgs is blake160 of public key, the args of secp256k1 single sign lock
+	LockArgs []byte
+	Path     string
+}
+
+// KeyStore manages key files of a directory, key files are named UTC--<time>--<lock args> as ckb-cli does.
+// Unlocked keys are kept in memory and zeroed when locked.
+type KeyStore struct {
+	dir     string
+	scryptN int
+	scryptP int
+
+	mu       sync.Mutex
+	unlocked map[string]*unlocked
+}
+
+type unlocked struct {
+	key   *secp256k1.Secp256k1Key
+	timer *time.Timer
+}
+
+func NewKeyStore(dir string, scryptN int, scryptP int) *KeyStore {
+	return &KeyStore{
+		dir:      dir,
+		scryptN:  scryptN,
+		scryptP:  scryptP,
+		unlocked: make(map[string]*unlocked),
+	}
+}
+
+// Accounts returns accounts of key files in directory sorted by file name, files are not decrypted
+func (ks *KeyStore) Accounts() ([]*Account, error) {
+	files, err := ioutil.ReadDir(ks.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read keystore directory error: %v", err)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name() < files[j].Name()
+	})
+
+	var accounts []*Account
+	for _, file := range files {
+		if file.IsDir() || strings.HasPrefix(file.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(ks.dir, file.Name())
+		lockArgs, err := readLockArgs(path)
+		if err != nil {
+			// skip files which are not key files
+			continue
+		}
+		accounts = append(accounts, &Account{LockArgs: lockArgs, Path: path})
+	}
+	return accounts, nil
+}
+
+// Find returns the account of lock args
+func (ks *KeyStore) Find(lockArgs []byte) (*Account, error) {
+	accounts, err := ks.Accounts()
+	if err != nil {
+		return nil, err
+	}
+	for _, account := range accounts {
+		if string(account.LockArgs) == string(lockArgs) {
+			return account, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %x", ErrAccountNotFound, lockArgs)
+}
+
+// NewAccount generates a random key and stores it encrypted with password
+func (ks *KeyStore) NewAccount(password string) (*Account, error) {
+	key, err := secp256k1.RandomNew()
+	if err != nil {
+		return nil, err
+	}
+	defer key.Zero()
+	return ks.Import(key, password)
+}
+
+// Import stores key encrypted with password
+func (ks *KeyStore) Import(key *secp256k1.Secp256k1Key, password string) (*Account, error) {
+	lockArgs, err := blake2b.Blake160(key.PubKey())
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ks.Find(lockArgs); err == nil {
+		return nil, fmt.Errorf("%w: %x", ErrAccountExists, lockArgs)
+	}
+	data, err := EncryptKey(key, password, ks.scryptN, ks.scryptP)
+	if err != nil {
+		return nil, err
+	}
+	return ks.write(lockArgs, data)
+}
+
+// ImportJSON stores a key file exported by ckb-cli or another keystore, it is encrypted again with newPassword
+func (ks *KeyStore) ImportJSON(data []byte, password string, newPassword string) (*Account, error) {
+	key, err := DecryptKey(data, password)
+	if err != nil {
+		return nil, err
+	}
+	defer key.Zero()
+	return ks.Import(key, newPassword)
+}
+
+// Export returns the key file of account encrypted with newPassword
+func (ks *KeyStore) Export(account *Account, password string, newPassword string) ([]byte, error) {
+	key, err := ks.decrypt(account, password)
+	if err != nil {
+		return nil, err
+	}
+	defer key.Zero()
+	return EncryptKey(key, newPassword, ks.scryptN, ks.scryptP)
+}
+
+// Update changes password of account
+func (ks *KeyStore) Update(account *Account, password string, newPassword string) error {
+	data, err := ks.Export(account, password, newPassword)
+	if err != nil {
+		return err
+	}
+	return writeFile(account.Path, data)
+}
+
+// Delete removes key file of account after checking password, the unlocked key is locked
+func (ks *KeyStore) Delete(account *Account, password string) error {
+	key, err := ks.decrypt(account, password)
+	if err != nil {
+		return err
+	}
+	key.Zero()
+	ks.Lock(account.LockArgs)
+	return os.Remove(account.Path)
+}
+
+// Unlock decrypts key of account and keeps it in memory until timeout, the key is kept until Lock when
+// timeout is 0. Unlocking an unlocked account resets its timeout.
+func (ks *KeyStore) Unlock(account *Account, password string, timeout time.Duration) error {
+	key, err := ks.decrypt(account, password)
+	if err != nil {
+		return err
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	id := hex.EncodeToString(account.LockArgs)
+	if u, ok := ks.unlocked[id]; ok {
+		ks.lock(id, u)
+	}
+	u := &unlocked{key: key}
+	if timeout > 0 {
+		u.timer = time.AfterFunc(timeout, func() {
+			ks.mu.Lock()
+			defer ks.mu.Unlock()
+			// the account may be unlocked again with a new key
+			if ks.unlocked[id] == u {
+				ks.lock(id, u)
+			}
+		})
+	}
+	ks.unlocked[id] = u
+	return nil
+}
+
+// Lock zeroes unlocked key of lock args
+func (ks *KeyStore) Lock(lockArgs []byte) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	id := hex.EncodeToString(lockArgs)
+	if u, ok := ks.unlocked[id]; ok {
+		ks.lock(id, u)
+	}
+}
+
+// LockAll zeroes all unlocked keys
+func (ks *KeyStore) LockAll() {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	for id, u := range ks.unlocked {
+		ks.lock(id, u)
+	}
+}
+
+func (ks *KeyStore) lock(id string, u *unlocked) {
+	if u.timer != nil {
+		u.timer.Stop()
+	}
+	u.key.Zero()
+	delete(ks.unlocked, id)
+}
+
+// Unlocked reports whether key of lock args is unlocked
+func (ks *KeyStore) Unlocked(lockArgs []byte) bool {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	_, ok := ks.unlocked[hex.EncodeToString(lockArgs)]
+	return ok
+}
+
+// Signer returns a crypto.Signer of unlocked account, it signs while the account is unlocked
+func (ks *KeyStore) Signer(account *Account) (crypto.Signer, error) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	u, ok := ks.unlocked[hex.EncodeToString(account.LockArgs)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %x", ErrLocked, account.LockArgs)
+	}
+	return &accountSigner{
+		ks:       ks,
+		lockArgs: account.LockArgs,
+		pubKey:   u.key.PubKey(),
+	}, nil
+}
+
+func (ks *KeyStore) decrypt(account *Account, password string) (*secp256k1.Secp256k1Key, error) {
+	data, err := ioutil.ReadFile(account.Path)
+	if err != nil {
+		return nil, fmt.Errorf("read key file error: %v", err)
+	}
+	key, err := DecryptKey(data, password)
+	if err != nil {
+		return nil, err
+	}
+	lockArgs, err := blake2b.Blake160(key.PubKey())
+	if err != nil {
+		return nil, err
+	}
+	if string(lockArgs) != string(account.LockArgs) {
+		key.Zero()
+		return nil, fmt.Errorf("key file %s is not of lock args %x", account.Path, account.LockArgs)
+	}
+	return key, nil
+}
+
+func (ks *KeyStore) write(lockArgs []byte, data []byte) (*Account, error) {
+	if err := os.MkdirAll(ks.dir, 0700); err != nil {
+		return nil, fmt.Errorf("create keystore directory error: %v", err)
+	}
+	path := filepath.Join(ks.dir, keyFileName(lockArgs, time.Now()))
+	if err := writeFile(path, data); err != nil {
+		return nil, err
+	}
+	return &Account{LockArgs: lockArgs, Path: path}, nil
+}
+
+type accountSigner struct {
+	ks       *KeyStore
+	lockArgs []byte
+	pubKey   []byte
+}
+
+func (s *accountSigner) PubKey() []byte {
+	return s.pubKey
+}
+
+func (s *accountSigner) Script(systemScripts *utils.SystemScripts) (*types.Script, error) {
+	return &types.Script{
+		CodeHash: systemScripts.SecpSingleSigCell.CellHash,
+		HashType: types.HashTypeType,
+		Args:     s.lockArgs,
+	}, nil
+}
+
+func (s *accountSigner) SignMessage(ctx context.Context, digest []byte) ([]byte, error) {
+	s.ks.mu.Lock()
+	defer s.ks.mu.Unlock()
+	u, ok := s.ks.unlocked[hex.EncodeToString(s.lockArgs)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %x", ErrLocked, s.lockArgs)
+	}
+	return u.key.Sign(digest)
+}
+
+// keyFileName returns UTC--<time>--<lock args> which ckb-cli uses
+func keyFileName(lockArgs []byte, t time.Time) string {
+	return fmt.Sprintf("UTC--%s--%x", t.UTC().Format("2006-01-02T15-04-05.000000000Z"), lockArgs)
+}
+
+// readLockArgs reads hash160 of key file, or lock args suffix of file name for key files without it
+func readLockArgs(path string) ([]byte, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var k keyJSON
+	if err := json.Unmarshal(data, &k); err != nil {
+		return nil, err
+	}
+	if k.Version != version {
+		return nil, fmt.Errorf("%w: version %d", ErrUnsupportedKeyFile, k.Version)
+	}
+	name := filepath.Base(path)
+	hash160 := strings.TrimPrefix(k.Hash160, "0x")
+	if hash160 == "" && strings.LastIndex(name, "--") >= 0 {
+		hash160 = name[strings.LastIndex(name, "--")+2:]
+	}
+	lockArgs, err := hex.DecodeString(hash160)
+	if err != nil || len(lockArgs) != 20 {
+		return nil, fmt.Errorf("%w: lock args of %s", ErrUnsupportedKeyFile, name)
+	}
+	return lockArgs, nil
+}
+
+// writeFile writes through a temporary file so that a key file is never partially written
+func writeFile(path string, data []byte) error {
+	f, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	if err != nil {
+		return fmt.Errorf("write key file error: %v", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return fmt.Errorf("write key file error: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("write key file error: %v", err)
+	}
+	if err := os.Chmod(f.Name(), 0600); err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("write key file error: %v", err)
+	}
+	return os.Rename(f.Name(), path)
+}
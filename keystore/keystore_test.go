@@ -0,0 +1,171 @@
+package keystore
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto/secp256k1"
+	"github.com/stretchr/testify/assert"
+
+	ckbsecp256k1 "github.com/ququzone/ckb-sdk-go/crypto/secp256k1"
+)
+
+const testPrivateKey = "7a28b5ba57c53603b0b07b56bba752f7784bf506fa95edc395f5cf6c7514fe9d"
+
+func TestDecryptKeyVectors(t *testing.T) {
+	// test vectors of Web3 Secret Storage definition with password testpassword
+	vectors := map[string]string{
+		"pbkdf2": `{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"6087dab2f9fdbbfaddc31a909735c1e6"},"ciphertext":"5318b4d5bcd28de64ee5559e671353e16f075ecae9f99c7a79a38af5f869aa46","kdf":"pbkdf2","kdfparams":{"c":262144,"dklen":32,"prf":"hmac-sha256","salt":"ae3cd4e7013836a3df6bd7241b12db061dbe2c6785853cce422d148a624ce0bd"},"mac":"517ead924a9d0dc3124507e3393d175ce3ff7c1e96529c6c555ce9e51205e9b2"},"id":"3198bc9c-6672-5ab3-d995-4942343ae5b6","version":3}`,
+		"scrypt": `{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"83dbcc02d8ccb40e466191a123791e0e"},"ciphertext":"d172bf743a674da9cdad04534d56926ef8358534d458fffccd4e6ad2fbde479c","kdf":"scrypt","kdfparams":{"dklen":32,"n":262144,"p":8,"r":1,"salt":"ab0c7876052600dd703518d6fc3fe8984592145b591fc8fb5c6d43190334ba19"},"mac":"2103ac29920d71da29f15d75b4a16dbe95cfd7ff8faea1056c33131d846e3097"},"id":"3198bc9c-6672-5ab3-d995-4942343ae5b6","version":3}`,
+	}
+	for name, vector := range vectors {
+		key, err := DecryptKey([]byte(vector), "testpassword")
+		if assert.NoError(t, err, name) {
+			assert.Equal(t, testPrivateKey, hexKey(key), name)
+		}
+		_, err = DecryptKey([]byte(vector), "wrong")
+		assert.True(t, errors.Is(err, ErrDecrypt), name)
+	}
+}
+
+func TestEncryptKey(t *testing.T) {
+	key, err := ckbsecp256k1.HexToKey(testPrivateKey)
+	assert.NoError(t, err)
+
+	data, err := EncryptKey(key, "password", LightScryptN, LightScryptP)
+	assert.NoError(t, err)
+	decrypted, err := DecryptKey(data, "password")
+	assert.NoError(t, err)
+	assert.Equal(t, testPrivateKey, hexKey(decrypted))
+
+	data, err = EncryptKeyPBKDF2(key, "password", 1024)
+	assert.NoError(t, err)
+	decrypted, err = DecryptKey(data, "password")
+	assert.NoError(t, err)
+	assert.Equal(t, testPrivateKey, hexKey(decrypted))
+
+	_, err = DecryptKey([]byte(`{"version":1}`), "password")
+	assert.True(t, errors.Is(err, ErrUnsupportedKeyFile))
+}
+
+func TestKeyStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keystore")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ks := NewKeyStore(dir, LightScryptN, LightScryptP)
+	key, err := ckbsecp256k1.HexToKey(testPrivateKey)
+	assert.NoError(t, err)
+	account, err := ks.Import(key, "password")
+	assert.NoError(t, err)
+	_, err = ks.Import(key, "password")
+	assert.True(t, errors.Is(err, ErrAccountExists))
+
+	created, err := ks.NewAccount("other")
+	assert.NoError(t, err)
+
+	accounts, err := ks.Accounts()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(accounts))
+	found, err := ks.Find(account.LockArgs)
+	assert.NoError(t, err)
+	assert.Equal(t, account.Path, found.Path)
+
+	_, err = ks.Signer(account)
+	assert.True(t, errors.Is(err, ErrLocked))
+	assert.True(t, errors.Is(ks.Unlock(account, "wrong", 0), ErrDecrypt))
+	assert.NoError(t, ks.Unlock(account, "password", 0))
+	signer, err := ks.Signer(account)
+	assert.NoError(t, err)
+	assert.Equal(t, key.PubKey(), signer.PubKey())
+
+	digest := make([]byte, 32)
+	signature, err := signer.SignMessage(context.Background(), digest)
+	assert.NoError(t, err)
+	pub, err := secp256k1.RecoverPubkey(digest, signature)
+	assert.NoError(t, err)
+	x, y := secp256k1.S256().Unmarshal(pub)
+	assert.Equal(t, key.PubKey(), secp256k1.CompressPubkey(x, y))
+
+	ks.Lock(account.LockArgs)
+	assert.False(t, ks.Unlocked(account.LockArgs))
+	_, err = signer.SignMessage(context.Background(), digest)
+	assert.True(t, errors.Is(err, ErrLocked))
+
+	assert.NoError(t, ks.Unlock(created, "other", 10*time.Millisecond))
+	assert.True(t, ks.Unlocked(created.LockArgs))
+	time.Sleep(100 * time.Millisecond)
+	assert.False(t, ks.Unlocked(created.LockArgs))
+
+	assert.NoError(t, ks.Update(account, "password", "new"))
+	exported, err := ks.Export(account, "new", "exported")
+	assert.NoError(t, err)
+	assert.NoError(t, ks.Delete(account, "new"))
+	_, err = ks.Find(account.LockArgs)
+	assert.True(t, errors.Is(err, ErrAccountNotFound))
+
+	imported, err := ks.ImportJSON(exported, "exported", "password")
+	assert.NoError(t, err)
+	assert.Equal(t, account.LockArgs, imported.LockArgs)
+}
+
+func hexKey(key *ckbsecp256k1.Secp256k1Key) string {
+	return hex.EncodeToString(key.Bytes())
+}
+
+func TestDecryptKeyParams(t *testing.T) {
+	key, err := ckbsecp256k1.HexToKey(testPrivateKey)
+	assert.NoError(t, err)
+	data, err := EncryptKey(key, "password", LightScryptN, LightScryptP)
+	assert.NoError(t, err)
+
+	var k keyJSON
+	assert.NoError(t, json.Unmarshal(data, &k))
+	for name, params := range map[string]map[string]interface{}{
+		"n not power of two": {"n": 4095},
+		"n too large":        {"n": 1 << 21},
+		"r zero":             {"r": 0},
+		"p too large":        {"p": 1 << 20},
+		"cost too large":     {"n": 1 << 20, "r": 8, "p": 2},
+		"dklen too large":    {"dklen": 1 << 20},
+		"n fraction":         {"n": 4096.5},
+		"n missing":          {"n": nil},
+	} {
+		crafted := k
+		crafted.Crypto.KDFParams = make(map[string]interface{})
+		for param, value := range k.Crypto.KDFParams {
+			crafted.Crypto.KDFParams[param] = value
+		}
+		for param, value := range params {
+			if value == nil {
+				delete(crafted.Crypto.KDFParams, param)
+			} else {
+				crafted.Crypto.KDFParams[param] = value
+			}
+		}
+		data, err := json.Marshal(&crafted)
+		assert.NoError(t, err)
+		_, err = DecryptKey(data, "password")
+		assert.True(t, errors.Is(err, ErrUnsupportedKeyFile), name)
+	}
+
+	_, err = EncryptKey(key, "password", 1000, 1)
+	assert.True(t, errors.Is(err, ErrUnsupportedKeyFile))
+	_, err = EncryptKeyPBKDF2(key, "password", 1<<30)
+	assert.True(t, errors.Is(err, ErrUnsupportedKeyFile))
+
+	data, err = EncryptKeyPBKDF2(key, "password", 1024)
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(data, &k))
+	k.Crypto.KDFParams["c"] = 1 << 30
+	data, err = json.Marshal(&k)
+	assert.NoError(t, err)
+	_, err = DecryptKey(data, "password")
+	assert.True(t, errors.Is(err, ErrUnsupportedKeyFile))
+}
@@ -45,6 +45,14 @@ func NewPayment(from, to string, amount, fee uint64) (*Payment, error) {
 }
 
 func (p *Payment) GenerateTx(client rpc.Client) (*types.Transaction, error) {
+	output := &types.CellOutput{
+		Capacity: p.Amount,
+		Lock:     p.To,
+	}
+	if err := output.CheckCapacity(nil); err != nil {
+		return nil, err
+	}
+
 	collector := utils.NewCellCollector(client, p.From, utils.NewCapacityCellProcessor(p.Amount+p.Fee))
 
 	result, err := collector.Collect()
@@ -62,18 +70,24 @@ func (p *Payment) GenerateTx(client rpc.Client) (*types.Transaction, error) {
 	}
 
 	tx := transaction.NewSecp256k1SingleSigTx(systemScripts)
-	tx.Outputs = append(tx.Outputs, &types.CellOutput{
-		Capacity: p.Amount,
-		Lock:     p.To,
-	})
+	// anyone-can-pay lock is signed the same as secp256k1 lock
+	if systemScripts.ACPCell != nil && p.From.CodeHash == systemScripts.ACPCell.CellHash {
+		tx.CellDeps = append(tx.CellDeps, &types.CellDep{
+			OutPoint: systemScripts.ACPCell.OutPoint,
+			DepType:  types.DepTypeDepGroup,
+		})
+	}
+	tx.Outputs = append(tx.Outputs, output)
 	tx.OutputsData = [][]byte{{}}
 
 	if result.Capacity-p.Amount-p.Fee > 0 {
-		if result.Capacity-p.Amount-p.Fee >= 6100000000 {
-			tx.Outputs = append(tx.Outputs, &types.CellOutput{
-				Capacity: result.Capacity - p.Amount - p.Fee,
-				Lock:     p.From,
-			})
+		change := &types.CellOutput{
+			Capacity: result.Capacity - p.Amount - p.Fee,
+			Lock:     p.From,
+		}
+		// change too small to be a cell goes to the receiver
+		if change.CheckCapacity(nil) == nil {
+			tx.Outputs = append(tx.Outputs, change)
 			tx.OutputsData = [][]byte{{}, {}}
 		} else {
 			tx.Outputs[0].Capacity = result.Capacity - p.Fee
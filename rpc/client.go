@@ -50,7 +50,7 @@ type Client interface {
 	// If second with_data argument set to true, will return cell data and data_hash if it is live.
 	GetLiveCell(ctx context.Context, outPoint *types.OutPoint, withData bool) (*types.CellWithStatus, error)
 
-	// GetTransaction returns the information about a transaction requested by transaction hash.
+	// GetTransaction returns the information about a transaction requested by transaction hash, NotFound when node does not know it.
 	GetTransaction(ctx context.Context, hash types.Hash) (*types.TransactionWithStatus, error)
 
 	// GetCellbaseOutputCapacityDetails returns each component of the created CKB in this block's cellbase,
@@ -269,18 +269,29 @@ func (cli *client) GetLiveCell(ctx context.Context, point *types.OutPoint, withD
 }
 
 func (cli *client) GetTransaction(ctx context.Context, hash types.Hash) (*types.TransactionWithStatus, error) {
-	var result transactionWithStatus
-	err := cli.c.CallContext(ctx, &result, "get_transaction", hash)
+	var raw json.RawMessage
+	err := cli.c.CallContext(ctx, &raw, "get_transaction", hash)
 	if err != nil {
 		return nil, err
+	} else if len(raw) == 0 || string(raw) == "null" {
+		return nil, NotFound
+	}
+
+	var result transactionWithStatus
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, err
 	}
-	return &types.TransactionWithStatus{
+	ret := &types.TransactionWithStatus{
 		Transaction: toTransaction(result.Transaction),
 		TxStatus: &types.TxStatus{
 			BlockHash: result.TxStatus.BlockHash,
 			Status:    result.TxStatus.Status,
 		},
-	}, err
+	}
+	if result.TxStatus.Reason != nil {
+		ret.TxStatus.Reason = *result.TxStatus.Reason
+	}
+	return ret, nil
 }
 
 func (cli *client) GetCellbaseOutputCapacityDetails(ctx context.Context, hash types.Hash) (*types.BlockReward, error) {
@@ -419,6 +430,8 @@ func (cli *client) GetLiveCellsByLockHash(ctx context.Context, lockHash types.Ha
 				Index:       uint(cell.CreatedBy.Index),
 				TxHash:      cell.CreatedBy.TxHash,
 			},
+			Cellbase:      cell.Cellbase,
+			OutputDataLen: uint64(cell.OutputDataLen),
 		}
 		if cell.CellOutput.Type != nil {
 			ret[i].CellOutput.Type = &types.Script{
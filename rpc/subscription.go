@@ -0,0 +1,503 @@
+package rpc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"sync"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/ququzone/ckb-sdk-go/types"
+)
+
+const (
+	// subscriptionQueueSize is the number of notifications buffered for a slow subscriber
+	subscriptionQueueSize = 1000
+
+	TopicNewTipHeader        = "new_tip_header"
+	TopicNewTipBlock         = "new_tip_block"
+	TopicNewTransaction      = "new_transaction"
+	TopicProposedTransaction = "proposed_transaction"
+	TopicRejectedTransaction = "rejected_transaction"
+)
+
+var (
+	ErrClientClosed              = errors.New("client is closed")
+	ErrSubscriptionQueueOverflow = errors.New("subscription queue overflow")
+)
+
+// Subscription is a notification stream created by SubscriptionClient.
+type Subscription interface {
+	// Err returns a channel which receives the error terminating the subscription, such as a closed connection.
+	// The channel is closed by Unsubscribe.
+	Err() <-chan error
+
+	// Unsubscribe stops notifications and closes the notification channel.
+	Unsubscribe()
+}
+
+// SubscriptionClient subscribes to node topics over WebSocket or TCP, the node must enable the matching
+// ws_listen_address or tcp_listen_address.
+type SubscriptionClient interface {
+	// SubscribeNewTipHeader notifies header of each new tip block.
+	SubscribeNewTipHeader(ctx context.Context) (<-chan *types.Header, Subscription, error)
+
+	// SubscribeNewTipBlock notifies each new tip block.
+	SubscribeNewTipBlock(ctx context.Context) (<-chan *types.Block, Subscription, error)
+
+	// SubscribeNewTransaction notifies transactions entering pool.
+	SubscribeNewTransaction(ctx context.Context) (<-chan *types.PoolTransactionEntry, Subscription, error)
+
+	// SubscribeProposedTransaction notifies transactions proposed in pool.
+	SubscribeProposedTransaction(ctx context.Context) (<-chan *types.PoolTransactionEntry, Subscription, error)
+
+	// SubscribeRejectedTransaction notifies transactions rejected by pool with the reason.
+	SubscribeRejectedTransaction(ctx context.Context) (<-chan *types.RejectedTransaction, Subscription, error)
+
+	// Close closes connection and ends all subscriptions.
+	Close()
+}
+
+// DialSubscription connects to node by url of scheme ws, wss or tcp, for example ws://127.0.0.1:28114
+// or tcp://127.0.0.1:18114.
+func DialSubscription(ctx context.Context, rawurl string) (SubscriptionClient, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+
+	var conn jsonConn
+	switch u.Scheme {
+	case "ws", "wss":
+		c, _, err := websocket.DefaultDialer.DialContext(ctx, rawurl, nil)
+		if err != nil {
+			return nil, err
+		}
+		conn = c
+	case "tcp":
+		var dialer net.Dialer
+		c, err := dialer.DialContext(ctx, "tcp", u.Host)
+		if err != nil {
+			return nil, err
+		}
+		conn = newTCPConn(c)
+	default:
+		return nil, fmt.Errorf("unsupported subscription scheme: %s", u.Scheme)
+	}
+
+	cli := &subscriptionClient{
+		conn:    conn,
+		pending: make(map[uint64]*pendingCall),
+		subs:    make(map[string]*subscription),
+		closed:  make(chan struct{}),
+	}
+	go cli.read()
+	return cli, nil
+}
+
+// jsonConn is implemented by websocket.Conn
+type jsonConn interface {
+	ReadJSON(v interface{}) error
+	WriteJSON(v interface{}) error
+	Close() error
+}
+
+// tcpConn sends and receives line delimited json
+type tcpConn struct {
+	conn    net.Conn
+	decoder *json.Decoder
+	encoder *json.Encoder
+}
+
+func newTCPConn(conn net.Conn) *tcpConn {
+	return &tcpConn{
+		conn:    conn,
+		decoder: json.NewDecoder(bufio.NewReader(conn)),
+		encoder: json.NewEncoder(conn),
+	}
+}
+
+func (c *tcpConn) ReadJSON(v interface{}) error {
+	return c.decoder.Decode(v)
+}
+
+func (c *tcpConn) WriteJSON(v interface{}) error {
+	return c.encoder.Encode(v)
+}
+
+func (c *tcpConn) Close() error {
+	return c.conn.Close()
+}
+
+type jsonError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type jsonMessage struct {
+	Version string          `json:"jsonrpc"`
+	ID      *uint64         `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *jsonError      `json:"error,omitempty"`
+}
+
+type notificationParams struct {
+	Result       json.RawMessage `json:"result"`
+	Subscription string          `json:"subscription"`
+}
+
+type pendingCall struct {
+	ch chan *jsonMessage
+	// sub is registered when its subscribe call succeeds, before reading any notification for it
+	sub *subscription
+}
+
+type subscriptionClient struct {
+	conn jsonConn
+
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	nextID  uint64
+	pending map[uint64]*pendingCall
+	subs    map[string]*subscription
+	err     error
+	closed  chan struct{}
+}
+
+func (cli *subscriptionClient) Close() {
+	cli.mu.Lock()
+	select {
+	case <-cli.closed:
+	default:
+		close(cli.closed)
+	}
+	cli.mu.Unlock()
+	_ = cli.conn.Close()
+}
+
+// read dispatches responses to callers and notifications to subscriptions until connection fails
+func (cli *subscriptionClient) read() {
+	var err error
+	for {
+		var msg jsonMessage
+		if err = cli.conn.ReadJSON(&msg); err != nil {
+			break
+		}
+		if msg.ID != nil {
+			cli.mu.Lock()
+			call, ok := cli.pending[*msg.ID]
+			delete(cli.pending, *msg.ID)
+			if ok && call.sub != nil && msg.Error == nil {
+				if err := json.Unmarshal(msg.Result, &call.sub.id); err == nil {
+					cli.subs[call.sub.id] = call.sub
+				}
+			}
+			cli.mu.Unlock()
+			if ok {
+				call.ch <- &msg
+			}
+			continue
+		}
+		if msg.Method != "subscribe" {
+			continue
+		}
+		var params notificationParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			continue
+		}
+		cli.mu.Lock()
+		sub, ok := cli.subs[params.Subscription]
+		cli.mu.Unlock()
+		if ok {
+			sub.push(params.Result)
+		}
+	}
+
+	select {
+	case <-cli.closed:
+		err = ErrClientClosed
+	default:
+	}
+
+	cli.mu.Lock()
+	cli.err = err
+	pending := cli.pending
+	subs := cli.subs
+	cli.pending = make(map[uint64]*pendingCall)
+	cli.subs = make(map[string]*subscription)
+	cli.mu.Unlock()
+
+	for _, call := range pending {
+		close(call.ch)
+	}
+	for _, sub := range subs {
+		sub.fail(err)
+	}
+}
+
+func (cli *subscriptionClient) call(ctx context.Context, result interface{}, method string, params ...interface{}) error {
+	return cli.callWith(ctx, nil, result, method, params...)
+}
+
+func (cli *subscriptionClient) callWith(ctx context.Context, sub *subscription, result interface{}, method string, params ...interface{}) error {
+	ch := make(chan *jsonMessage, 1)
+
+	cli.mu.Lock()
+	if cli.err != nil {
+		err := cli.err
+		cli.mu.Unlock()
+		return err
+	}
+	id := cli.nextID
+	cli.nextID++
+	cli.pending[id] = &pendingCall{ch: ch, sub: sub}
+	cli.mu.Unlock()
+
+	cli.writeMu.Lock()
+	err := cli.conn.WriteJSON(&jsonMessage{
+		Version: "2.0",
+		ID:      &id,
+		Method:  method,
+		Params:  mustMarshal(params),
+	})
+	cli.writeMu.Unlock()
+	if err != nil {
+		cli.mu.Lock()
+		delete(cli.pending, id)
+		cli.mu.Unlock()
+		return err
+	}
+
+	select {
+	case msg, ok := <-ch:
+		if !ok {
+			cli.mu.Lock()
+			err := cli.err
+			cli.mu.Unlock()
+			return err
+		}
+		if msg.Error != nil {
+			return fmt.Errorf("%s (code %d)", msg.Error.Message, msg.Error.Code)
+		}
+		return json.Unmarshal(msg.Result, result)
+	case <-ctx.Done():
+		cli.mu.Lock()
+		delete(cli.pending, id)
+		cli.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+func mustMarshal(params []interface{}) json.RawMessage {
+	if params == nil {
+		params = []interface{}{}
+	}
+	data, _ := json.Marshal(params)
+	return data
+}
+
+// subscribe registers topic, deliver decodes a notification and sends it to the typed channel unless quit is closed
+func (cli *subscriptionClient) subscribe(ctx context.Context, topic string, deliver func(data []byte, quit <-chan struct{}) error, done func()) (Subscription, error) {
+	sub := &subscription{
+		client: cli,
+		queue:  make(chan []byte, subscriptionQueueSize),
+		err:    make(chan error, 1),
+		quit:   make(chan struct{}),
+	}
+
+	var id string
+	if err := cli.callWith(ctx, sub, &id, "subscribe", topic); err != nil {
+		// the node may have accepted it when ctx is done
+		cli.mu.Lock()
+		for key, s := range cli.subs {
+			if s == sub {
+				delete(cli.subs, key)
+			}
+		}
+		cli.mu.Unlock()
+		return nil, err
+	}
+
+	go sub.run(deliver, done)
+	return sub, nil
+}
+
+type subscription struct {
+	client *subscriptionClient
+	id     string
+	queue  chan []byte
+	err    chan error
+	quit   chan struct{}
+	once   sync.Once
+}
+
+func (sub *subscription) Err() <-chan error {
+	return sub.err
+}
+
+func (sub *subscription) Unsubscribe() {
+	sub.once.Do(func() {
+		cli := sub.client
+		cli.mu.Lock()
+		_, active := cli.subs[sub.id]
+		delete(cli.subs, sub.id)
+		cli.mu.Unlock()
+
+		if active {
+			var result bool
+			_ = cli.call(context.Background(), &result, "unsubscribe", sub.id)
+		}
+		close(sub.quit)
+		close(sub.err)
+	})
+}
+
+// fail ends subscription with err, it is called when client stops reading
+func (sub *subscription) fail(err error) {
+	sub.once.Do(func() {
+		sub.err <- err
+		close(sub.quit)
+		close(sub.err)
+	})
+}
+
+// push queues a notification without blocking client reading
+func (sub *subscription) push(data json.RawMessage) {
+	select {
+	case sub.queue <- data:
+	default:
+		sub.client.mu.Lock()
+		delete(sub.client.subs, sub.id)
+		sub.client.mu.Unlock()
+		go sub.fail(ErrSubscriptionQueueOverflow)
+	}
+}
+
+func (sub *subscription) run(deliver func(data []byte, quit <-chan struct{}) error, done func()) {
+	defer done()
+	for {
+		select {
+		case data := <-sub.queue:
+			// node sends result as a json encoded string
+			var s string
+			if err := json.Unmarshal(data, &s); err == nil {
+				data = []byte(s)
+			}
+			if err := deliver(data, sub.quit); err != nil {
+				sub.fail(fmt.Errorf("decode notification error: %v", err))
+				return
+			}
+		case <-sub.quit:
+			return
+		}
+	}
+}
+
+func (cli *subscriptionClient) SubscribeNewTipHeader(ctx context.Context) (<-chan *types.Header, Subscription, error) {
+	ch := make(chan *types.Header)
+	sub, err := cli.subscribe(ctx, TopicNewTipHeader, func(data []byte, quit <-chan struct{}) error {
+		var result header
+		if err := json.Unmarshal(data, &result); err != nil {
+			return err
+		}
+		select {
+		case ch <- toHeader(result):
+		case <-quit:
+		}
+		return nil
+	}, func() { close(ch) })
+	if err != nil {
+		return nil, nil, err
+	}
+	return ch, sub, nil
+}
+
+func (cli *subscriptionClient) SubscribeNewTipBlock(ctx context.Context) (<-chan *types.Block, Subscription, error) {
+	ch := make(chan *types.Block)
+	sub, err := cli.subscribe(ctx, TopicNewTipBlock, func(data []byte, quit <-chan struct{}) error {
+		var result block
+		if err := json.Unmarshal(data, &result); err != nil {
+			return err
+		}
+		select {
+		case ch <- &types.Block{
+			Header:       toHeader(result.Header),
+			Proposals:    result.Proposals,
+			Transactions: toTransactions(result.Transactions),
+			Uncles:       toUncles(result.Uncles),
+		}:
+		case <-quit:
+		}
+		return nil
+	}, func() { close(ch) })
+	if err != nil {
+		return nil, nil, err
+	}
+	return ch, sub, nil
+}
+
+func (cli *subscriptionClient) subscribePoolTransaction(ctx context.Context, topic string) (<-chan *types.PoolTransactionEntry, Subscription, error) {
+	ch := make(chan *types.PoolTransactionEntry)
+	sub, err := cli.subscribe(ctx, topic, func(data []byte, quit <-chan struct{}) error {
+		var result poolTransactionEntry
+		if err := json.Unmarshal(data, &result); err != nil {
+			return err
+		}
+		select {
+		case ch <- toPoolTransactionEntry(result):
+		case <-quit:
+		}
+		return nil
+	}, func() { close(ch) })
+	if err != nil {
+		return nil, nil, err
+	}
+	return ch, sub, nil
+}
+
+func (cli *subscriptionClient) SubscribeNewTransaction(ctx context.Context) (<-chan *types.PoolTransactionEntry, Subscription, error) {
+	return cli.subscribePoolTransaction(ctx, TopicNewTransaction)
+}
+
+func (cli *subscriptionClient) SubscribeProposedTransaction(ctx context.Context) (<-chan *types.PoolTransactionEntry, Subscription, error) {
+	return cli.subscribePoolTransaction(ctx, TopicProposedTransaction)
+}
+
+func (cli *subscriptionClient) SubscribeRejectedTransaction(ctx context.Context) (<-chan *types.RejectedTransaction, Subscription, error) {
+	ch := make(chan *types.RejectedTransaction)
+	sub, err := cli.subscribe(ctx, TopicRejectedTransaction, func(data []byte, quit <-chan struct{}) error {
+		// notification is a tuple of entry and reject reason
+		var result []json.RawMessage
+		if err := json.Unmarshal(data, &result); err != nil {
+			return err
+		}
+		if len(result) != 2 {
+			return fmt.Errorf("invalid rejected transaction: %s", data)
+		}
+		var entry poolTransactionEntry
+		if err := json.Unmarshal(result[0], &entry); err != nil {
+			return err
+		}
+		var reason types.PoolTransactionReject
+		if err := json.Unmarshal(result[1], &reason); err != nil {
+			return err
+		}
+		select {
+		case ch <- &types.RejectedTransaction{Entry: toPoolTransactionEntry(entry), Reason: &reason}:
+		case <-quit:
+		}
+		return nil
+	}, func() { close(ch) })
+	if err != nil {
+		return nil, nil, err
+	}
+	return ch, sub, nil
+}
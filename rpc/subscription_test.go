@@ -0,0 +1,120 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+const headerJSON = `{"compact_target":"0x1a08a97e","dao":"0x0000000000000000000000000000000000000000000000000000000000000000","epoch":"0x1","hash":"0x0000000000000000000000000000000000000000000000000000000000000001","nonce":"0x0","number":"0x400","parent_hash":"0x0000000000000000000000000000000000000000000000000000000000000000","proposals_hash":"0x0000000000000000000000000000000000000000000000000000000000000000","timestamp":"0x5cd2b117","transactions_root":"0x0000000000000000000000000000000000000000000000000000000000000000","uncles_hash":"0x0000000000000000000000000000000000000000000000000000000000000000","version":"0x0"}`
+
+const txJSON = `{"version":"0x0","hash":"0x0000000000000000000000000000000000000000000000000000000000000002","cell_deps":[],"header_deps":[],"inputs":[],"outputs":[],"outputs_data":[],"witnesses":[]}`
+
+// serve answers subscribe and unsubscribe, then sends one notification of each topic
+func serve(t *testing.T, conn jsonConn, unsubscribed chan<- string) {
+	defer conn.Close()
+	for {
+		var msg jsonMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			return
+		}
+		var params []string
+		assert.Nil(t, json.Unmarshal(msg.Params, &params))
+		switch msg.Method {
+		case "subscribe":
+			id := "0x" + params[0]
+			assert.Nil(t, conn.WriteJSON(&jsonMessage{Version: "2.0", ID: msg.ID, Result: json.RawMessage(`"` + id + `"`)}))
+
+			var result string
+			switch params[0] {
+			case TopicNewTipHeader:
+				result = headerJSON
+			case TopicNewTransaction:
+				result = `{"transaction":` + txJSON + `,"cycles":"0x10","size":"0x20","fee":"0x30"}`
+			case TopicRejectedTransaction:
+				result = `[{"transaction":` + txJSON + `,"cycles":"0x10","size":"0x20","fee":"0x0"},{"type":"LowFeeRate","description":"fee too low"}]`
+			}
+			data, _ := json.Marshal(result)
+			notification, _ := json.Marshal(&notificationParams{Result: data, Subscription: id})
+			assert.Nil(t, conn.WriteJSON(&jsonMessage{Version: "2.0", Method: "subscribe", Params: notification}))
+		case "unsubscribe":
+			assert.Nil(t, conn.WriteJSON(&jsonMessage{Version: "2.0", ID: msg.ID, Result: json.RawMessage(`true`)}))
+			unsubscribed <- params[0]
+		}
+	}
+}
+
+func testSubscriptions(t *testing.T, client SubscriptionClient, unsubscribed <-chan string) {
+	headers, sub, err := client.SubscribeNewTipHeader(context.Background())
+	assert.Nil(t, err)
+	header := <-headers
+	assert.Equal(t, uint64(1024), header.Number)
+	sub.Unsubscribe()
+	assert.Equal(t, "0x"+TopicNewTipHeader, <-unsubscribed)
+	_, ok := <-headers
+	assert.False(t, ok)
+	_, ok = <-sub.Err()
+	assert.False(t, ok)
+
+	entries, _, err := client.SubscribeNewTransaction(context.Background())
+	assert.Nil(t, err)
+	entry := <-entries
+	assert.Equal(t, uint64(0x30), entry.Fee)
+	assert.Equal(t, "0x0000000000000000000000000000000000000000000000000000000000000002", entry.Transaction.Hash.String())
+
+	rejected, sub, err := client.SubscribeRejectedTransaction(context.Background())
+	assert.Nil(t, err)
+	reject := <-rejected
+	assert.Equal(t, "LowFeeRate", reject.Reason.Type)
+	assert.Equal(t, uint64(0x10), reject.Entry.Cycles)
+
+	client.Close()
+	assert.Equal(t, ErrClientClosed, <-sub.Err())
+}
+
+func TestSubscribeTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer listener.Close()
+
+	unsubscribed := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		serve(t, newTCPConn(conn), unsubscribed)
+	}()
+
+	client, err := DialSubscription(context.Background(), "tcp://"+listener.Addr().String())
+	assert.Nil(t, err)
+	testSubscriptions(t, client, unsubscribed)
+}
+
+func TestSubscribeWebSocket(t *testing.T) {
+	unsubscribed := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		serve(t, conn, unsubscribed)
+	}))
+	defer server.Close()
+
+	client, err := DialSubscription(context.Background(), "ws"+strings.TrimPrefix(server.URL, "http"))
+	assert.Nil(t, err)
+	testSubscriptions(t, client, unsubscribed)
+}
+
+func TestDialSubscriptionScheme(t *testing.T) {
+	_, err := DialSubscription(context.Background(), "http://127.0.0.1:8114")
+	assert.Error(t, err)
+}
@@ -120,6 +120,7 @@ type transactionWithStatus struct {
 	TxStatus    struct {
 		BlockHash *types.Hash             `json:"block_hash"`
 		Status    types.TransactionStatus `json:"status"`
+		Reason    *string                 `json:"reason"`
 	} `json:"tx_status"`
 }
 
@@ -152,8 +153,10 @@ type transactionPoint struct {
 }
 
 type liveCell struct {
-	CellOutput cellOutput       `json:"cell_output"`
-	CreatedBy  transactionPoint `json:"created_by"`
+	CellOutput    cellOutput       `json:"cell_output"`
+	CreatedBy     transactionPoint `json:"created_by"`
+	Cellbase      bool             `json:"cellbase"`
+	OutputDataLen hexutil.Uint64   `json:"output_data_len"`
 }
 
 type cellTransaction struct {
@@ -477,3 +480,19 @@ func toNode(result node) *types.Node {
 	}
 	return ret
 }
+
+type poolTransactionEntry struct {
+	Transaction transaction    `json:"transaction"`
+	Cycles      hexutil.Uint64 `json:"cycles"`
+	Size        hexutil.Uint64 `json:"size"`
+	Fee         hexutil.Uint64 `json:"fee"`
+}
+
+func toPoolTransactionEntry(entry poolTransactionEntry) *types.PoolTransactionEntry {
+	return &types.PoolTransactionEntry{
+		Transaction: toTransaction(entry.Transaction),
+		Cycles:      uint64(entry.Cycles),
+		Size:        uint64(entry.Size),
+		Fee:         uint64(entry.Fee),
+	}
+}
@@ -0,0 +1,160 @@
+package transaction
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ququzone/ckb-sdk-go/types"
+	"github.com/ququzone/ckb-sdk-go/utils"
+)
+
+var (
+	ErrInsufficientCapacity = errors.New("insufficient capacity")
+)
+
+// Builder completes a transaction with desired outputs: it collects inputs, pays fee by fee rate and
+// returns the remaining capacity to change lock.
+type Builder struct {
+	// Transaction holds cell deps and desired outputs, it may also hold inputs added by caller
+	Transaction *types.Transaction
+	// Collector provides input cells, its processor is replaced when building
+	Collector *utils.CellCollector
+	// ChangeLock receives the remaining capacity
+	ChangeLock *types.Script
+	// FeeRate is shannons per 1000 bytes
+	FeeRate uint64
+	// InputCapacity is the capacity of inputs already in Transaction
+	InputCapacity uint64
+}
+
+func NewBuilder(tx *types.Transaction, collector *utils.CellCollector, changeLock *types.Script, feeRate uint64) *Builder {
+	return &Builder{
+		Transaction: tx,
+		Collector:   collector,
+		ChangeLock:  changeLock,
+		FeeRate:     feeRate,
+	}
+}
+
+// Build collects inputs until outputs and fee are paid, a change output is added when the remaining capacity
+// can hold it, otherwise more inputs are collected. When cells run out with outputs and fee paid but the
+// remaining capacity below the occupied capacity of a change output, the remaining capacity goes to fee.
+// Returns group and witness args of collected inputs for signing, both are nil when inputs already in
+// transaction are enough.
+func (b *Builder) Build() ([]int, *types.WitnessArgs, error) {
+	if b.Transaction == nil {
+		return nil, nil, errors.New("must init transaction first")
+	}
+
+	processor := &builderProcessor{builder: b}
+	if len(b.Transaction.Inputs) > 0 {
+		tx, _, _, balanced, err := b.complete(nil, 0)
+		if err != nil {
+			return nil, nil, err
+		}
+		if balanced {
+			*b.Transaction = *tx
+			return nil, nil, nil
+		}
+		processor.fallback = tx
+	}
+
+	collector := *b.Collector
+	collector.Processor = processor
+	result, err := collector.Collect()
+	if err != nil {
+		return nil, nil, fmt.Errorf("collect cell error: %v", err)
+	}
+	if processor.tx == nil && processor.fallback != nil {
+		processor.tx = processor.fallback
+		processor.group = processor.fallbackGroup
+		processor.witnessArgs = processor.fallbackWitnessArgs
+	}
+	if processor.tx == nil {
+		return nil, nil, fmt.Errorf("%w: collected %d", ErrInsufficientCapacity, b.InputCapacity+result.Capacity)
+	}
+
+	*b.Transaction = *processor.tx
+	return processor.group, processor.witnessArgs, nil
+}
+
+// complete returns a copy of transaction with cells as inputs and outputs paid, the returned transaction is nil
+// when capacity is not enough. balanced is false when the remaining capacity can't hold a change output and
+// would go to fee.
+func (b *Builder) complete(cells []*types.Cell, capacity uint64) (tx *types.Transaction, group []int, witnessArgs *types.WitnessArgs, balanced bool, err error) {
+	tx = b.Transaction.Clone()
+	if len(cells) > 0 {
+		group, witnessArgs, err = AddInputsForTransaction(tx, cells)
+		if err != nil {
+			return nil, nil, nil, false, err
+		}
+	}
+
+	total := b.InputCapacity + capacity
+	var outputs uint64
+	for _, output := range tx.Outputs {
+		outputs += output.Capacity
+	}
+	if total < outputs {
+		return nil, nil, nil, false, nil
+	}
+
+	change := &types.CellOutput{
+		Lock: b.ChangeLock,
+	}
+	tx.Outputs = append(tx.Outputs, change)
+	tx.OutputsData = append(tx.OutputsData, []byte{})
+	fee, err := CalculateTransactionFee(tx, b.FeeRate)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	if total >= outputs+fee+change.OccupiedCapacity(nil) {
+		change.Capacity = total - outputs - fee
+		return tx, group, witnessArgs, true, nil
+	}
+
+	tx.Outputs = tx.Outputs[:len(tx.Outputs)-1]
+	tx.OutputsData = tx.OutputsData[:len(tx.OutputsData)-1]
+	fee, err = CalculateTransactionFee(tx, b.FeeRate)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	if total < outputs+fee {
+		return nil, nil, nil, false, nil
+	}
+	return tx, group, witnessArgs, total == outputs+fee, nil
+}
+
+type builderProcessor struct {
+	builder     *Builder
+	tx          *types.Transaction
+	group       []int
+	witnessArgs *types.WitnessArgs
+	// fallback is the last transaction whose remaining capacity goes to fee, it is used when cells run out
+	fallback            *types.Transaction
+	fallbackGroup       []int
+	fallbackWitnessArgs *types.WitnessArgs
+}
+
+func (p *builderProcessor) Process(cell *types.Cell, result *utils.CollectResult) (bool, error) {
+	result.Capacity = result.Capacity + cell.Capacity
+	result.Cells = append(result.Cells, cell)
+
+	tx, group, witnessArgs, balanced, err := p.builder.complete(result.Cells, result.Capacity)
+	if err != nil {
+		return false, err
+	}
+	if tx == nil {
+		return false, nil
+	}
+	if !balanced {
+		p.fallback = tx
+		p.fallbackGroup = group
+		p.fallbackWitnessArgs = witnessArgs
+		return false, nil
+	}
+	p.tx = tx
+	p.group = group
+	p.witnessArgs = witnessArgs
+	return true, nil
+}
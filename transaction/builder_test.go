@@ -0,0 +1,104 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ququzone/ckb-sdk-go/rpc"
+	"github.com/ququzone/ckb-sdk-go/types"
+	"github.com/ququzone/ckb-sdk-go/utils"
+)
+
+type cellsClient struct {
+	rpc.Client
+	cells []*types.Cell
+}
+
+func (c *cellsClient) GetTipHeader(ctx context.Context) (*types.Header, error) {
+	return &types.Header{Number: 10}, nil
+}
+
+func (c *cellsClient) GetCellsByLockHash(ctx context.Context, hash types.Hash, from uint64, to uint64) ([]*types.Cell, error) {
+	if from > 0 {
+		return nil, nil
+	}
+	return c.cells, nil
+}
+
+func testBuilder(capacities ...uint64) *Builder {
+	client := &cellsClient{}
+	for i, capacity := range capacities {
+		client.cells = append(client.cells, &types.Cell{
+			Capacity: capacity,
+			Lock:     testLock(),
+			OutPoint: &types.OutPoint{TxHash: types.HexToHash("0x01"), Index: uint(i)},
+		})
+	}
+	tx, _ := testVerifyTx()
+	tx.Inputs = nil
+	tx.Witnesses = nil
+	tx.Outputs = tx.Outputs[:1]
+	tx.OutputsData = tx.OutputsData[:1]
+	return NewBuilder(tx, utils.NewCellCollector(client, testLock(), nil), testLock(), 1000)
+}
+
+func TestBuilderBuild(t *testing.T) {
+	builder := testBuilder(10000000000, 10000000000, 10000000000)
+	group, witnessArgs, err := builder.Build()
+	assert.Nil(t, err)
+	assert.Equal(t, []int{0, 1}, group)
+	assert.Equal(t, EmptyWitnessArg, witnessArgs)
+	assert.Nil(t, builder.Collector.Processor)
+
+	tx := builder.Transaction
+	assert.Equal(t, 2, len(tx.Inputs))
+	assert.Equal(t, 2, len(tx.Outputs))
+	assert.Equal(t, 2, len(tx.OutputsData))
+
+	fee, err := CalculateTransactionFee(tx, 1000)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(20000000000)-10000000000-fee, tx.Outputs[1].Capacity)
+
+	inputs := []*types.CellOutput{
+		{Capacity: 10000000000, Lock: testLock()},
+		{Capacity: 10000000000, Lock: testLock()},
+	}
+	assert.Nil(t, Verify(tx, inputs))
+}
+
+func TestBuilderChangeCapacity(t *testing.T) {
+	// 150 CKB pays the output but can not hold change, so one more cell is collected
+	builder := testBuilder(15000000000, 10000000000)
+	group, _, err := builder.Build()
+	assert.Nil(t, err)
+	assert.Equal(t, []int{0, 1}, group)
+	assert.Equal(t, 2, len(builder.Transaction.Outputs))
+}
+
+func TestBuilderInsufficient(t *testing.T) {
+	builder := testBuilder(6100000000, 3000000000)
+	_, _, err := builder.Build()
+	assert.True(t, errors.Is(err, ErrInsufficientCapacity))
+	assert.Equal(t, 0, len(builder.Transaction.Inputs))
+}
+
+func TestBuilderSurplusToFee(t *testing.T) {
+	builder := testBuilder(10000000000, 2000000000)
+	group, _, err := builder.Build()
+	assert.Nil(t, err)
+	assert.Equal(t, []int{0, 1}, group)
+
+	tx := builder.Transaction
+	assert.Equal(t, 2, len(tx.Inputs))
+	assert.Equal(t, 1, len(tx.Outputs))
+	assert.Equal(t, uint64(10000000000), tx.Outputs[0].Capacity)
+
+	inputs := []*types.CellOutput{
+		{Capacity: 10000000000, Lock: testLock()},
+		{Capacity: 2000000000, Lock: testLock()},
+	}
+	assert.Nil(t, Verify(tx, inputs))
+}
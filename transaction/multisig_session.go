@@ -0,0 +1,255 @@
+package transaction
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/ququzone/ckb-sdk-go/crypto"
+	"github.com/ququzone/ckb-sdk-go/crypto/blake2b"
+	"github.com/ququzone/ckb-sdk-go/crypto/secp256k1"
+	"github.com/ququzone/ckb-sdk-go/rpc"
+	"github.com/ququzone/ckb-sdk-go/types"
+)
+
+var (
+	ErrNotCosigner           = errors.New("signer is not in multisig script")
+	ErrThresholdNotReached   = errors.New("multisig threshold not reached")
+	ErrInvalidMultisigScript = errors.New("invalid multisig script")
+)
+
+// MultisigSession collects signatures of a multisig lock group from cosigners one by one, it is exported
+// to JSON to pass between cosigners and finalized once the threshold is reached.
+// The transaction must not change after the session is created, other witnesses of the group included.
+type MultisigSession struct {
+	Transaction *types.Transaction
+	Group       []int
+	// WitnessArgs is the first witness of group, its lock is replaced when finalizing
+	WitnessArgs *types.WitnessArgs
+	// Multisig is the multisig script serialization S | R | M | N | blake160(pubkey) * N
+	Multisig []byte
+	// signatures are indexed by position of pubkey hash in Multisig
+	signatures map[int][]byte
+}
+
+// NewMultisigSession creates a session of group, witnessArgs keeps input and output type of the first witness
+// of group and may be nil when both are empty.
+func NewMultisigSession(tx *types.Transaction, group []int, witnessArgs *types.WitnessArgs, multisig []byte) (*MultisigSession, error) {
+	if len(group) == 0 {
+		return nil, errors.New("group is empty")
+	}
+	if witnessArgs == nil {
+		witnessArgs = &types.WitnessArgs{}
+	}
+	session := &MultisigSession{
+		Transaction: tx,
+		Group:       group,
+		WitnessArgs: witnessArgs,
+		Multisig:    multisig,
+		signatures:  make(map[int][]byte),
+	}
+	if err := session.check(); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+func (s *MultisigSession) check() error {
+	if len(s.Multisig) < 4 || s.Multisig[0] != 0 || len(s.Multisig) != 4+20*int(s.Multisig[3]) {
+		return fmt.Errorf("%w: length %d", ErrInvalidMultisigScript, len(s.Multisig))
+	}
+	if s.RequireFirstN() > s.Threshold() || s.Threshold() > int(s.Multisig[3]) || s.Threshold() == 0 {
+		return fmt.Errorf("%w: require first %d, threshold %d of %d", ErrInvalidMultisigScript, s.RequireFirstN(), s.Threshold(), s.Multisig[3])
+	}
+	for _, index := range s.Group {
+		if index < 0 || index >= len(s.Transaction.Witnesses) {
+			return fmt.Errorf("group index %d out of witnesses", index)
+		}
+	}
+	return nil
+}
+
+func (s *MultisigSession) RequireFirstN() int {
+	return int(s.Multisig[1])
+}
+
+func (s *MultisigSession) Threshold() int {
+	return int(s.Multisig[2])
+}
+
+// PubKeyHashes returns blake160 of cosigner pubkeys in script order
+func (s *MultisigSession) PubKeyHashes() [][]byte {
+	hashes := make([][]byte, s.Multisig[3])
+	for i := range hashes {
+		hashes[i] = s.Multisig[4+20*i : 24+20*i]
+	}
+	return hashes
+}
+
+// Signed returns pubkey hashes that have signed in script order
+func (s *MultisigSession) Signed() [][]byte {
+	var signed [][]byte
+	for i, hash := range s.PubKeyHashes() {
+		if _, ok := s.signatures[i]; ok {
+			signed = append(signed, hash)
+		}
+	}
+	return signed
+}
+
+// Message returns the message every cosigner signs, the witness lock is the script with threshold signature placeholders
+func (s *MultisigSession) Message() ([]byte, error) {
+	lock := append([]byte{}, s.Multisig...)
+	for i := 0; i < s.Threshold(); i++ {
+		lock = append(lock, SignaturePlaceholder...)
+	}
+	return groupSigningMessage(s.Transaction, s.Group, &types.WitnessArgs{
+		Lock:       lock,
+		InputType:  s.WitnessArgs.InputType,
+		OutputType: s.WitnessArgs.OutputType,
+	})
+}
+
+func (s *MultisigSession) Sign(key crypto.Key) error {
+	return s.sign(key.Sign)
+}
+
+func (s *MultisigSession) SignWithSigner(ctx context.Context, signer crypto.Signer) error {
+	return s.sign(func(message []byte) ([]byte, error) {
+		return signer.SignMessage(ctx, message)
+	})
+}
+
+func (s *MultisigSession) sign(sign signFunc) error {
+	message, err := s.Message()
+	if err != nil {
+		return err
+	}
+	signature, err := sign(message)
+	if err != nil {
+		return err
+	}
+	return s.AddSignature(signature)
+}
+
+// AddSignature adds a signature made offline over Message, the cosigner is recovered from signature
+func (s *MultisigSession) AddSignature(signature []byte) error {
+	message, err := s.Message()
+	if err != nil {
+		return err
+	}
+	pub, err := secp256k1.RecoverPubKey(message, signature)
+	if err != nil {
+		return fmt.Errorf("recover signature error: %v", err)
+	}
+	hash, err := blake2b.Blake160(pub)
+	if err != nil {
+		return err
+	}
+	for i, pubKeyHash := range s.PubKeyHashes() {
+		if string(pubKeyHash) == string(hash) {
+			s.signatures[i] = signature
+			return nil
+		}
+	}
+	return ErrNotCosigner
+}
+
+// Complete reports whether threshold signatures are collected including all of the first RequireFirstN cosigners
+func (s *MultisigSession) Complete() bool {
+	for i := 0; i < s.RequireFirstN(); i++ {
+		if _, ok := s.signatures[i]; !ok {
+			return false
+		}
+	}
+	return len(s.signatures) >= s.Threshold()
+}
+
+// Finalize writes the multisig witness into transaction and returns it
+func (s *MultisigSession) Finalize() (*types.Transaction, error) {
+	if !s.Complete() {
+		return nil, fmt.Errorf("%w: %d of %d signed", ErrThresholdNotReached, len(s.signatures), s.Threshold())
+	}
+	indexes := make([]int, 0, len(s.signatures))
+	for i := range s.signatures {
+		indexes = append(indexes, i)
+	}
+	// first N cosigners are signed and sorted first
+	sort.Ints(indexes)
+
+	lock := append([]byte{}, s.Multisig...)
+	for _, i := range indexes[:s.Threshold()] {
+		lock = append(lock, s.signatures[i]...)
+	}
+	wa := &types.WitnessArgs{
+		Lock:       lock,
+		InputType:  s.WitnessArgs.InputType,
+		OutputType: s.WitnessArgs.OutputType,
+	}
+	witness, err := wa.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	s.Transaction.Witnesses[s.Group[0]] = witness
+	return s.Transaction, nil
+}
+
+type multisigSession struct {
+	Transaction json.RawMessage          `json:"transaction"`
+	Group       []int                    `json:"group"`
+	InputType   hexutil.Bytes            `json:"input_type,omitempty"`
+	OutputType  hexutil.Bytes            `json:"output_type,omitempty"`
+	Multisig    hexutil.Bytes            `json:"multisig_script"`
+	Signatures  map[string]hexutil.Bytes `json:"signatures"`
+}
+
+// MarshalJSON exports session with signatures keyed by pubkey hash
+func (s *MultisigSession) MarshalJSON() ([]byte, error) {
+	tx, err := rpc.TransactionString(s.Transaction)
+	if err != nil {
+		return nil, err
+	}
+	signatures := make(map[string]hexutil.Bytes)
+	hashes := s.PubKeyHashes()
+	for i, signature := range s.signatures {
+		signatures[hexutil.Encode(hashes[i])] = signature
+	}
+	return json.Marshal(&multisigSession{
+		Transaction: json.RawMessage(tx),
+		Group:       s.Group,
+		InputType:   s.WitnessArgs.InputType,
+		OutputType:  s.WitnessArgs.OutputType,
+		Multisig:    s.Multisig,
+		Signatures:  signatures,
+	})
+}
+
+func (s *MultisigSession) UnmarshalJSON(input []byte) error {
+	var result multisigSession
+	if err := json.Unmarshal(input, &result); err != nil {
+		return err
+	}
+	tx, err := rpc.TransactionFromString(string(result.Transaction))
+	if err != nil {
+		return err
+	}
+	session, err := NewMultisigSession(tx, result.Group, &types.WitnessArgs{
+		InputType:  result.InputType,
+		OutputType: result.OutputType,
+	}, result.Multisig)
+	if err != nil {
+		return err
+	}
+	// signatures are verified again as the file may be edited
+	for hash, signature := range result.Signatures {
+		if err := session.AddSignature(signature); err != nil {
+			return fmt.Errorf("signature of %s error: %w", hash, err)
+		}
+	}
+	*s = *session
+	return nil
+}
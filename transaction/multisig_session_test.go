@@ -0,0 +1,78 @@
+package transaction
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ququzone/ckb-sdk-go/crypto/blake2b"
+	"github.com/ququzone/ckb-sdk-go/crypto/secp256k1"
+	"github.com/ququzone/ckb-sdk-go/types"
+)
+
+func TestMultisigSession(t *testing.T) {
+	var keys []*secp256k1.Secp256k1Key
+	// require first 1, threshold 2 of 3
+	multisig := []byte{0, 1, 2, 3}
+	for i := 0; i < 3; i++ {
+		key, err := secp256k1.RandomNew()
+		assert.Nil(t, err)
+		keys = append(keys, key)
+		hash, err := blake2b.Blake160(key.PubKey())
+		assert.Nil(t, err)
+		multisig = append(multisig, hash...)
+	}
+
+	newTx := func() *types.Transaction {
+		tx, _ := testVerifyTx()
+		tx.Inputs = append(tx.Inputs, &types.CellInput{
+			PreviousOutput: &types.OutPoint{TxHash: types.HexToHash("0x0c"), Index: 1},
+		})
+		tx.Witnesses = [][]byte{{}, {1, 2, 3}}
+		return tx
+	}
+
+	session, err := NewMultisigSession(newTx(), []int{0, 1}, &types.WitnessArgs{}, multisig)
+	assert.Nil(t, err)
+	assert.Nil(t, session.Sign(keys[2]))
+	assert.Nil(t, session.Sign(keys[1]))
+	// the first cosigner is required
+	assert.False(t, session.Complete())
+	_, err = session.Finalize()
+	assert.True(t, errors.Is(err, ErrThresholdNotReached))
+
+	stranger, err := secp256k1.RandomNew()
+	assert.Nil(t, err)
+	assert.Equal(t, ErrNotCosigner, session.Sign(stranger))
+
+	data, err := json.Marshal(session)
+	assert.Nil(t, err)
+	imported := &MultisigSession{}
+	assert.Nil(t, json.Unmarshal(data, imported))
+	assert.Equal(t, session.Signed(), imported.Signed())
+
+	assert.Nil(t, imported.Sign(keys[0]))
+	assert.True(t, imported.Complete())
+	tx, err := imported.Finalize()
+	assert.Nil(t, err)
+
+	expected := newTx()
+	err = MultiSignTransaction(expected, []int{0, 1}, &types.WitnessArgs{}, append([]byte{}, multisig...), keys[0], keys[1])
+	assert.Nil(t, err)
+	assert.Equal(t, expected.Witnesses, tx.Witnesses)
+
+	session, err = NewMultisigSession(newTx(), []int{0, 1}, nil, multisig)
+	assert.Nil(t, err)
+	assert.Nil(t, session.Sign(keys[0]))
+	assert.Nil(t, session.Sign(keys[1]))
+	_, err = json.Marshal(session)
+	assert.Nil(t, err)
+	tx, err = session.Finalize()
+	assert.Nil(t, err)
+	assert.Equal(t, expected.Witnesses, tx.Witnesses)
+
+	_, err = NewMultisigSession(newTx(), []int{0, 1}, &types.WitnessArgs{}, multisig[:30])
+	assert.True(t, errors.Is(err, ErrInvalidMultisigScript))
+}
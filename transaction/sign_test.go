@@ -0,0 +1,74 @@
+package transaction
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ququzone/ckb-sdk-go/crypto/secp256k1"
+	"github.com/ququzone/ckb-sdk-go/types"
+)
+
+func testSignTx() *types.Transaction {
+	return &types.Transaction{
+		Version:    0,
+		HeaderDeps: []types.Hash{},
+		CellDeps: []*types.CellDep{
+			{
+				OutPoint: &types.OutPoint{TxHash: types.HexToHash("0x71a7ba8fc96349fea0ed3a5c47992e3b4084b031a42264a018e0072e8172e46c"), Index: 0},
+				DepType:  types.DepTypeDepGroup,
+			},
+		},
+		Inputs: []*types.CellInput{
+			{PreviousOutput: &types.OutPoint{TxHash: types.HexToHash("0xa563884b3686078ec7e7677a5f86449b15cf2693f3c1241766c6996f206cc541"), Index: 0}},
+			{PreviousOutput: &types.OutPoint{TxHash: types.HexToHash("0xa563884b3686078ec7e7677a5f86449b15cf2693f3c1241766c6996f206cc541"), Index: 1}},
+		},
+		Outputs: []*types.CellOutput{
+			{
+				Capacity: 20000000000,
+				Lock: &types.Script{
+					CodeHash: types.HexToHash(SECP256K1_BLAKE160_SIGHASH_ALL_TYPE_HASH),
+					HashType: types.HashTypeType,
+					Args:     common.FromHex("0x36c329ed630d6ce750712a477543672adab57f4c"),
+				},
+			},
+		},
+		OutputsData: [][]byte{{}},
+		Witnesses:   [][]byte{{}, common.FromHex("0x10000000100000001000000010000000")},
+	}
+}
+
+// The vector is computed by an independent implementation of the sighash all algorithm
+// with RFC 6979 signatures, witnesses of the group other than the first are hashed with their content.
+func TestSingleSignTransactionVector(t *testing.T) {
+	key, err := secp256k1.HexToKey("e79f3207ea4980b7fed79956d5934249ceac4751a4fae01a0f7c4a96884bc4e3")
+	assert.Nil(t, err)
+
+	tx := testSignTx()
+	hash, err := tx.ComputeHash()
+	assert.Nil(t, err)
+	assert.Equal(t, "0x045c231168e450f1453eb187b2db7f4ec31f0f10f35eba30b3a44e246a2ef11e", hash.String())
+
+	message, err := SingleSegmentSignMessage(tx, 0, 2, &types.WitnessArgs{Lock: SignaturePlaceholder})
+	assert.Nil(t, err)
+	assert.Equal(t, "26eea244e664db114648a31671eb579e2f65350a275bc1977971aec366e2e06f", common.Bytes2Hex(message))
+
+	err = SingleSignTransaction(tx, []int{0, 1}, &types.WitnessArgs{Lock: SignaturePlaceholder}, key)
+	assert.Nil(t, err)
+	assert.Equal(t, "5500000010000000550000005500000041000000c19e106dd87df038a236a527b1e91f3749344b1075935a0e12d8813ffa3317b441a5bd39ae518feb756ebf0f413729bcadf8e95dfd6c32d3250fe10411ccc4da01", common.Bytes2Hex(tx.Witnesses[0]))
+	assert.Equal(t, "10000000100000001000000010000000", common.Bytes2Hex(tx.Witnesses[1]))
+}
+
+// MultiSignTransaction builds the message the same way, the vector is a 1 of 1 multisig of the same key
+func TestMultiSignTransactionVector(t *testing.T) {
+	key, err := secp256k1.HexToKey("e79f3207ea4980b7fed79956d5934249ceac4751a4fae01a0f7c4a96884bc4e3")
+	assert.Nil(t, err)
+
+	tx := testSignTx()
+	serialize := common.FromHex("0x0000010136c329ed630d6ce750712a477543672adab57f4c")
+	err = MultiSignTransaction(tx, []int{0, 1}, &types.WitnessArgs{}, serialize, key)
+	assert.Nil(t, err)
+	assert.Equal(t, "6d000000100000006d0000006d000000590000000000010136c329ed630d6ce750712a477543672adab57f4c1156edfd86eef808a7d30c9d94a9e3c2b282aadddede59b9ffb767d448fd9f0b100a5077eb3f8c77cfa65aa39bcd48d7d2e98ad29f3e6e77dbc2b0519aa236b601", common.Bytes2Hex(tx.Witnesses[0]))
+	assert.Equal(t, "10000000100000001000000010000000", common.Bytes2Hex(tx.Witnesses[1]))
+}
@@ -0,0 +1,192 @@
+package transaction
+
+import (
+	"fmt"
+
+	"github.com/ququzone/ckb-sdk-go/crypto/blake2b"
+	"github.com/ququzone/ckb-sdk-go/crypto/secp256k1"
+	"github.com/ququzone/ckb-sdk-go/types"
+)
+
+// Kinds of VerifySignatures, a lock group is reported at its first input
+const (
+	VerifyErrorInvalidWitness        VerifyErrorKind = "InvalidWitness"
+	VerifyErrorInvalidMultisigScript VerifyErrorKind = "InvalidMultisigScript"
+	VerifyErrorSignatureMismatch     VerifyErrorKind = "SignatureMismatch"
+)
+
+// VerifySignatures checks signatures of secp256k1 single sign and multisig lock groups offline,
+// inputs are the cell outputs spent by tx.Inputs in the same order.
+// The signing message of a group is rebuilt as SingleSignTransaction does, then the signer recovered from
+// each signature is checked against the lock args, for multisig the threshold and require first n are checked too.
+// Groups of other locks and the since of multisig args are not checked.
+// Returns nil or VerifyErrors.
+func VerifySignatures(tx *types.Transaction, inputs []*types.CellOutput) error {
+	if len(inputs) != len(tx.Inputs) {
+		return VerifyErrors{{
+			Kind:    VerifyErrorUnresolvedInputs,
+			Index:   -1,
+			Message: fmt.Sprintf("%d inputs but %d resolved inputs", len(tx.Inputs), len(inputs)),
+		}}
+	}
+
+	var errs VerifyErrors
+	add := func(kind VerifyErrorKind, index int, format string, args ...interface{}) {
+		errs = append(errs, &VerifyError{Kind: kind, Index: index, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for i, input := range inputs {
+		if input == nil || input.Lock == nil {
+			add(VerifyErrorUnresolvedInputs, i, "resolved input has no lock")
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	groups, err := lockGroups(inputs)
+	if err != nil {
+		return err
+	}
+	for _, group := range groups {
+		lock := inputs[group[0]].Lock
+		var multisig bool
+		switch {
+		case lock.HashType != types.HashTypeType:
+			continue
+		case lock.CodeHash == types.HexToHash(SECP256K1_BLAKE160_SIGHASH_ALL_TYPE_HASH):
+			multisig = false
+		case lock.CodeHash == types.HexToHash(SECP256K1_BLAKE160_MULTISIG_ALL_TYPE_HASH):
+			multisig = true
+		default:
+			continue
+		}
+
+		index := group[0]
+		if index >= len(tx.Witnesses) {
+			add(VerifyErrorMissingWitnesses, index, "witness of lock group is missing")
+			continue
+		}
+		var witnessArgs types.WitnessArgs
+		if err := witnessArgs.Deserialize(tx.Witnesses[index]); err != nil {
+			add(VerifyErrorInvalidWitness, index, "witness is not WitnessArgs: %v", err)
+			continue
+		}
+
+		if multisig {
+			if kind, message := verifyMultisigGroup(tx, group, &witnessArgs, lock.Args); kind != "" {
+				add(kind, index, "%s", message)
+			}
+			continue
+		}
+
+		if len(witnessArgs.Lock) != len(SignaturePlaceholder) {
+			add(VerifyErrorInvalidWitness, index, "lock of %d bytes is not a signature", len(witnessArgs.Lock))
+			continue
+		}
+		message, err := groupSigningMessage(tx, group, &types.WitnessArgs{
+			Lock:       SignaturePlaceholder,
+			InputType:  witnessArgs.InputType,
+			OutputType: witnessArgs.OutputType,
+		})
+		if err != nil {
+			return err
+		}
+		if len(lock.Args) != 20 || !secp256k1.VerifyLockArgs(lock.Args, message, witnessArgs.Lock) {
+			add(VerifyErrorSignatureMismatch, index, "signature is not made by key of lock args %#x", lock.Args)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// verifyMultisigGroup checks the multisig witness lock S | R | M | N | blake160(pubkey) * N | signature * M,
+// returns the kind and message of the violation or an empty kind
+func verifyMultisigGroup(tx *types.Transaction, group []int, witnessArgs *types.WitnessArgs, args []byte) (VerifyErrorKind, string) {
+	lock := witnessArgs.Lock
+	if len(lock) < 4 || lock[0] != 0 {
+		return VerifyErrorInvalidMultisigScript, "multisig script is malformed"
+	}
+	requireFirstN, threshold, count := int(lock[1]), int(lock[2]), int(lock[3])
+	if threshold == 0 || requireFirstN > threshold || threshold > count {
+		return VerifyErrorInvalidMultisigScript, fmt.Sprintf("require first %d, threshold %d of %d", requireFirstN, threshold, count)
+	}
+	scriptLength := 4 + 20*count
+	if len(lock) != scriptLength+len(SignaturePlaceholder)*threshold {
+		return VerifyErrorInvalidWitness, fmt.Sprintf("lock of %d bytes does not have %d signatures", len(lock), threshold)
+	}
+	script := lock[:scriptLength]
+	hash, err := blake2b.Blake160(script)
+	if err != nil {
+		return VerifyErrorInvalidMultisigScript, err.Error()
+	}
+	if len(args) < 20 || string(hash) != string(args[:20]) {
+		return VerifyErrorInvalidMultisigScript, fmt.Sprintf("multisig script hash %#x does not match lock args %#x", hash, args)
+	}
+
+	placeholder := append([]byte{}, script...)
+	for i := 0; i < threshold; i++ {
+		placeholder = append(placeholder, SignaturePlaceholder...)
+	}
+	message, err := groupSigningMessage(tx, group, &types.WitnessArgs{
+		Lock:       placeholder,
+		InputType:  witnessArgs.InputType,
+		OutputType: witnessArgs.OutputType,
+	})
+	if err != nil {
+		return VerifyErrorInvalidWitness, err.Error()
+	}
+
+	signed := make([]bool, count)
+	for i := 0; i < threshold; i++ {
+		signature := lock[scriptLength+65*i : scriptLength+65*(i+1)]
+		pub, err := secp256k1.RecoverPubKey(message, signature)
+		if err != nil {
+			return VerifyErrorSignatureMismatch, fmt.Sprintf("signature %d: %v", i, err)
+		}
+		pubKeyHash, err := blake2b.Blake160(pub)
+		if err != nil {
+			return VerifyErrorSignatureMismatch, err.Error()
+		}
+		matched := false
+		for j := 0; j < count; j++ {
+			if !signed[j] && string(script[4+20*j:24+20*j]) == string(pubKeyHash) {
+				signed[j] = true
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return VerifyErrorSignatureMismatch, fmt.Sprintf("signature %d is not made by a cosigner or is duplicated", i)
+		}
+	}
+	for i := 0; i < requireFirstN; i++ {
+		if !signed[i] {
+			return VerifyErrorSignatureMismatch, fmt.Sprintf("cosigner %d of the first %d has not signed", i, requireFirstN)
+		}
+	}
+	return "", ""
+}
+
+// lockGroups groups input indexes by lock script hash in the order of first appearance
+func lockGroups(inputs []*types.CellOutput) ([][]int, error) {
+	var groups [][]int
+	positions := make(map[types.Hash]int)
+	for i, input := range inputs {
+		hash, err := input.Lock.Hash()
+		if err != nil {
+			return nil, err
+		}
+		position, ok := positions[hash]
+		if !ok {
+			position = len(groups)
+			positions[hash] = position
+			groups = append(groups, nil)
+		}
+		groups[position] = append(groups[position], i)
+	}
+	return groups, nil
+}
@@ -0,0 +1,106 @@
+package transaction
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ququzone/ckb-sdk-go/crypto/blake2b"
+	"github.com/ququzone/ckb-sdk-go/crypto/secp256k1"
+	"github.com/ququzone/ckb-sdk-go/types"
+)
+
+func TestVerifySignatures(t *testing.T) {
+	key, err := secp256k1.RandomNew()
+	assert.Nil(t, err)
+	singleArgs, _ := blake2b.Blake160(key.PubKey())
+	singleLock := &types.Script{
+		CodeHash: types.HexToHash(SECP256K1_BLAKE160_SIGHASH_ALL_TYPE_HASH),
+		HashType: types.HashTypeType,
+		Args:     singleArgs,
+	}
+
+	var keys []*secp256k1.Secp256k1Key
+	// require first 1, threshold 2 of 3
+	multisig := []byte{0, 1, 2, 3}
+	for i := 0; i < 3; i++ {
+		k, err := secp256k1.RandomNew()
+		assert.Nil(t, err)
+		keys = append(keys, k)
+		hash, _ := blake2b.Blake160(k.PubKey())
+		multisig = append(multisig, hash...)
+	}
+	multisigArgs, _ := blake2b.Blake160(multisig)
+	multisigLock := &types.Script{
+		CodeHash: types.HexToHash(SECP256K1_BLAKE160_MULTISIG_ALL_TYPE_HASH),
+		HashType: types.HashTypeType,
+		Args:     multisigArgs,
+	}
+
+	newTx := func() (*types.Transaction, []*types.CellOutput) {
+		tx, _ := testVerifyTx()
+		tx.Inputs = append(tx.Inputs, &types.CellInput{
+			PreviousOutput: &types.OutPoint{TxHash: types.HexToHash("0x02"), Index: 0},
+		})
+		tx.Witnesses = [][]byte{{}, {}, {}}
+		inputs := []*types.CellOutput{
+			{Capacity: 10000000000, Lock: singleLock},
+			{Capacity: 6200000000, Lock: multisigLock},
+			{Capacity: 100000000, Lock: singleLock},
+		}
+		return tx, inputs
+	}
+	sign := func(tx *types.Transaction, first *secp256k1.Secp256k1Key, second *secp256k1.Secp256k1Key) {
+		assert.Nil(t, SingleSignTransaction(tx, []int{0, 2}, &types.WitnessArgs{Lock: SignaturePlaceholder}, key))
+		err := MultiSignTransaction(tx, []int{1}, &types.WitnessArgs{}, append([]byte{}, multisig...), first, second)
+		assert.Nil(t, err)
+	}
+
+	tx, inputs := newTx()
+	sign(tx, keys[0], keys[2])
+	assert.Nil(t, VerifySignatures(tx, inputs))
+
+	// outputs changed after signing
+	tx.Outputs[0].Capacity--
+	err = VerifySignatures(tx, inputs)
+	if assert.NotNil(t, err) {
+		errs := err.(VerifyErrors)
+		assert.Equal(t, 2, len(errs))
+		assert.True(t, errs.Has(VerifyErrorSignatureMismatch))
+	}
+
+	// the first cosigner is required
+	tx, inputs = newTx()
+	sign(tx, keys[1], keys[2])
+	err = VerifySignatures(tx, inputs)
+	if assert.NotNil(t, err) {
+		errs := err.(VerifyErrors)
+		assert.Equal(t, 1, len(errs))
+		assert.Equal(t, 1, errs[0].Index)
+		assert.Equal(t, VerifyErrorSignatureMismatch, errs[0].Kind)
+	}
+
+	// the same cosigner twice
+	tx, inputs = newTx()
+	sign(tx, keys[0], keys[0])
+	assert.True(t, VerifySignatures(tx, inputs).(VerifyErrors).Has(VerifyErrorSignatureMismatch))
+
+	// signed by another key
+	tx, inputs = newTx()
+	sign(tx, keys[0], keys[1])
+	other, _ := secp256k1.RandomNew()
+	assert.Nil(t, SingleSignTransaction(tx, []int{0, 2}, &types.WitnessArgs{Lock: SignaturePlaceholder}, other))
+	assert.True(t, VerifySignatures(tx, inputs).(VerifyErrors).Has(VerifyErrorSignatureMismatch))
+
+	tx.Witnesses[1] = []byte{1, 2, 3}
+	assert.True(t, VerifySignatures(tx, inputs).(VerifyErrors).Has(VerifyErrorInvalidWitness))
+
+	tx, inputs = newTx()
+	inputs[0] = nil
+	inputs[1] = &types.CellOutput{Capacity: inputs[1].Capacity}
+	errs := VerifySignatures(tx, inputs).(VerifyErrors)
+	assert.Equal(t, 2, len(errs))
+	assert.Equal(t, 0, errs[0].Index)
+	assert.Equal(t, 1, errs[1].Index)
+	assert.True(t, errs.Has(VerifyErrorUnresolvedInputs))
+}
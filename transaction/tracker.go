@@ -0,0 +1,175 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ququzone/ckb-sdk-go/rpc"
+	"github.com/ququzone/ckb-sdk-go/types"
+)
+
+// DefaultTrackInterval is the polling interval of NewTxTracker, a block is produced every 8 seconds on average.
+const DefaultTrackInterval = 2 * time.Second
+
+var (
+	ErrTransactionRejected = errors.New("transaction rejected")
+)
+
+// TxState is a snapshot of a tracked transaction
+type TxState struct {
+	Status types.TransactionStatus
+	// BlockHash and BlockNumber are set when committed
+	BlockHash   *types.Hash
+	BlockNumber uint64
+	// Confirmations is the number of blocks from the committed block to tip, 1 when committed in tip
+	Confirmations uint64
+	// Reorged is set when the transaction was committed in a block that is no longer in the chain
+	Reorged bool
+	// Reason is the node message of a rejected transaction
+	Reason string
+}
+
+// TxTracker follows status of a sent transaction by polling the node
+type TxTracker struct {
+	Client   rpc.Client
+	Hash     types.Hash
+	Interval time.Duration
+
+	last         *TxState
+	blockNumbers map[types.Hash]uint64
+}
+
+func NewTxTracker(client rpc.Client, hash types.Hash) *TxTracker {
+	return &TxTracker{
+		Client:   client,
+		Hash:     hash,
+		Interval: DefaultTrackInterval,
+	}
+}
+
+// Poll fetches the current state once, a transaction unknown to node has status TransactionStatusUnknown.
+func (t *TxTracker) Poll(ctx context.Context) (*TxState, error) {
+	state := &TxState{Status: types.TransactionStatusUnknown}
+
+	tx, err := t.Client.GetTransaction(ctx, t.Hash)
+	if err != nil && err != rpc.NotFound {
+		return nil, fmt.Errorf("get transaction error: %v", err)
+	}
+	if err == nil && tx.TxStatus != nil {
+		state.Status = tx.TxStatus.Status
+		state.Reason = tx.TxStatus.Reason
+		if state.Status == types.TransactionStatusCommitted && tx.TxStatus.BlockHash == nil {
+			state.Status = types.TransactionStatusUnknown
+		}
+	}
+
+	if state.Status == types.TransactionStatusCommitted {
+		blockHash := *tx.TxStatus.BlockHash
+		number, err := t.blockNumber(ctx, blockHash)
+		if err != nil {
+			return nil, err
+		}
+		canonical, err := t.Client.GetBlockHash(ctx, number)
+		if err != nil {
+			return nil, fmt.Errorf("get block hash of %d error: %v", number, err)
+		}
+		if *canonical == blockHash {
+			tip, err := t.Client.GetTipHeader(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("get tip header error: %v", err)
+			}
+			state.BlockHash = &blockHash
+			state.BlockNumber = number
+			if tip.Number >= number {
+				state.Confirmations = tip.Number - number + 1
+			}
+		} else {
+			// node has not caught up the reorg yet
+			state.Status = types.TransactionStatusUnknown
+		}
+	}
+
+	if t.last != nil && t.last.Status == types.TransactionStatusCommitted {
+		if state.Status != types.TransactionStatusCommitted || *state.BlockHash != *t.last.BlockHash {
+			state.Reorged = true
+		}
+	}
+	t.last = state
+	return state, nil
+}
+
+// Track polls every Interval and calls fn with each state different from the previous one,
+// it returns when fn returns true or ctx is done.
+func (t *TxTracker) Track(ctx context.Context, fn func(*TxState) bool) error {
+	var previous *TxState
+	for {
+		state, err := t.Poll(ctx)
+		if err != nil {
+			return err
+		}
+		if previous == nil || !sameState(state, previous) {
+			if fn(state) {
+				return nil
+			}
+		}
+		previous = state
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(t.Interval):
+		}
+	}
+}
+
+// WaitForTransaction blocks until transaction is committed with at least confirmations,
+// a reorged transaction is waited until committed again. It returns ErrTransactionRejected when pool rejects it.
+func WaitForTransaction(ctx context.Context, client rpc.Client, hash types.Hash, confirmations uint64) (*TxState, error) {
+	var result *TxState
+	var rejected error
+	err := NewTxTracker(client, hash).Track(ctx, func(state *TxState) bool {
+		if state.Status == types.TransactionStatusRejected {
+			rejected = fmt.Errorf("%w: %s", ErrTransactionRejected, state.Reason)
+			return true
+		}
+		if state.Status == types.TransactionStatusCommitted && state.Confirmations >= confirmations {
+			result = state
+			return true
+		}
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	if rejected != nil {
+		return nil, rejected
+	}
+	return result, nil
+}
+
+func (t *TxTracker) blockNumber(ctx context.Context, hash types.Hash) (uint64, error) {
+	if number, ok := t.blockNumbers[hash]; ok {
+		return number, nil
+	}
+	header, err := t.Client.GetHeader(ctx, hash)
+	if err != nil {
+		return 0, fmt.Errorf("get block header %s error: %v", hash.String(), err)
+	}
+	if t.blockNumbers == nil {
+		t.blockNumbers = make(map[types.Hash]uint64)
+	}
+	t.blockNumbers[hash] = header.Number
+	return header.Number, nil
+}
+
+func sameState(a, b *TxState) bool {
+	if a.Status != b.Status || a.Confirmations != b.Confirmations || a.Reorged != b.Reorged || a.Reason != b.Reason {
+		return false
+	}
+	if a.BlockHash == nil || b.BlockHash == nil {
+		return a.BlockHash == b.BlockHash
+	}
+	return *a.BlockHash == *b.BlockHash
+}
@@ -0,0 +1,136 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ququzone/ckb-sdk-go/rpc"
+	"github.com/ququzone/ckb-sdk-go/types"
+)
+
+// chainClient replays statuses of a transaction, one for each GetTransaction
+type chainClient struct {
+	rpc.Client
+	statuses []*types.TxStatus
+	tips     []uint64
+	headers  map[types.Hash]uint64
+	chain    map[uint64]types.Hash
+	polls    int
+}
+
+func (c *chainClient) GetTransaction(ctx context.Context, hash types.Hash) (*types.TransactionWithStatus, error) {
+	i := c.polls
+	if i >= len(c.statuses) {
+		i = len(c.statuses) - 1
+	}
+	c.polls++
+	if c.statuses[i] == nil {
+		return nil, rpc.NotFound
+	}
+	return &types.TransactionWithStatus{TxStatus: c.statuses[i]}, nil
+}
+
+func (c *chainClient) GetHeader(ctx context.Context, hash types.Hash) (*types.Header, error) {
+	return &types.Header{Hash: hash, Number: c.headers[hash]}, nil
+}
+
+func (c *chainClient) GetBlockHash(ctx context.Context, number uint64) (*types.Hash, error) {
+	hash := c.chain[number]
+	return &hash, nil
+}
+
+func (c *chainClient) GetTipHeader(ctx context.Context) (*types.Header, error) {
+	i := c.polls - 1
+	if i >= len(c.tips) {
+		i = len(c.tips) - 1
+	}
+	return &types.Header{Number: c.tips[i]}, nil
+}
+
+func TestTxTracker(t *testing.T) {
+	blockA := types.HexToHash("0x0a")
+	blockB := types.HexToHash("0x0b")
+	client := &chainClient{
+		statuses: []*types.TxStatus{
+			nil,
+			{Status: types.TransactionStatusPending},
+			{Status: types.TransactionStatusProposed},
+			{Status: types.TransactionStatusCommitted, BlockHash: &blockA},
+			{Status: types.TransactionStatusPending},
+			{Status: types.TransactionStatusCommitted, BlockHash: &blockB},
+			{Status: types.TransactionStatusCommitted, BlockHash: &blockB},
+		},
+		tips:    []uint64{0, 0, 0, 10, 10, 11, 12},
+		headers: map[types.Hash]uint64{blockA: 10, blockB: 11},
+		chain:   map[uint64]types.Hash{10: blockA, 11: blockB},
+	}
+
+	tracker := NewTxTracker(client, types.HexToHash("0x01"))
+	tracker.Interval = time.Millisecond
+	var states []*TxState
+	err := tracker.Track(context.Background(), func(state *TxState) bool {
+		states = append(states, state)
+		return state.Confirmations >= 2
+	})
+	assert.Nil(t, err)
+
+	var statuses []types.TransactionStatus
+	for _, state := range states {
+		statuses = append(statuses, state.Status)
+	}
+	assert.Equal(t, []types.TransactionStatus{
+		types.TransactionStatusUnknown,
+		types.TransactionStatusPending,
+		types.TransactionStatusProposed,
+		types.TransactionStatusCommitted,
+		types.TransactionStatusPending,
+		types.TransactionStatusCommitted,
+		types.TransactionStatusCommitted,
+	}, statuses)
+	assert.Equal(t, uint64(1), states[3].Confirmations)
+	assert.True(t, states[4].Reorged)
+	assert.Equal(t, blockB, *states[6].BlockHash)
+	assert.Equal(t, uint64(11), states[6].BlockNumber)
+}
+
+func TestTxTrackerStaleBlock(t *testing.T) {
+	blockA := types.HexToHash("0x0a")
+	client := &chainClient{
+		statuses: []*types.TxStatus{{Status: types.TransactionStatusCommitted, BlockHash: &blockA}},
+		tips:     []uint64{10},
+		headers:  map[types.Hash]uint64{blockA: 10},
+		chain:    map[uint64]types.Hash{10: types.HexToHash("0x0b")},
+	}
+	state, err := NewTxTracker(client, types.HexToHash("0x01")).Poll(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, types.TransactionStatusUnknown, state.Status)
+}
+
+func TestWaitForTransaction(t *testing.T) {
+	block := types.HexToHash("0x0a")
+	client := &chainClient{
+		statuses: []*types.TxStatus{{Status: types.TransactionStatusCommitted, BlockHash: &block}},
+		tips:     []uint64{12},
+		headers:  map[types.Hash]uint64{block: 10},
+		chain:    map[uint64]types.Hash{10: block},
+	}
+	state, err := WaitForTransaction(context.Background(), client, types.HexToHash("0x01"), 3)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(3), state.Confirmations)
+
+	client = &chainClient{
+		statuses: []*types.TxStatus{{Status: types.TransactionStatusRejected, Reason: "LowFeeRate"}},
+	}
+	_, err = WaitForTransaction(context.Background(), client, types.HexToHash("0x01"), 1)
+	assert.True(t, errors.Is(err, ErrTransactionRejected))
+
+	client = &chainClient{statuses: []*types.TxStatus{nil}}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = WaitForTransaction(ctx, client, types.HexToHash("0x01"), 1)
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
@@ -1,6 +1,7 @@
 package transaction
 
 import (
+	"context"
 	"encoding/binary"
 	"errors"
 
@@ -73,7 +74,7 @@ func AddInputsForTransaction(transaction *types.Transaction, cells []*types.Cell
 	for i := 0; i < len(cells); i++ {
 		cell := cells[i]
 		input := &types.CellInput{
-			Since: 0,
+			Since: LockSince(cell.Lock),
 			PreviousOutput: &types.OutPoint{
 				TxHash: cell.OutPoint.TxHash,
 				Index:  cell.OutPoint.Index,
@@ -87,17 +88,27 @@ func AddInputsForTransaction(transaction *types.Transaction, cells []*types.Cell
 	return group, EmptyWitnessArg, nil
 }
 
-func SingleSignTransaction(transaction *types.Transaction, group []int, witnessArgs *types.WitnessArgs, key crypto.Key) error {
+// LockSince returns the since required by lock, it is the last 8 bytes of multisig args with since, 0 for other locks
+func LockSince(lock *types.Script) uint64 {
+	if lock == nil || lock.CodeHash.String() != SECP256K1_BLAKE160_MULTISIG_ALL_TYPE_HASH ||
+		lock.HashType != types.HashTypeType || len(lock.Args) != 28 {
+		return 0
+	}
+	return binary.LittleEndian.Uint64(lock.Args[20:])
+}
+
+// groupSigningMessage returns the sighash all message of group, witnessArgs is the first witness with lock placeholder
+func groupSigningMessage(transaction *types.Transaction, group []int, witnessArgs *types.WitnessArgs) ([]byte, error) {
 	data, err := witnessArgs.Serialize()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	length := make([]byte, 8)
 	binary.LittleEndian.PutUint64(length, uint64(len(data)))
 
 	hash, err := transaction.ComputeHash()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	message := append(hash.Bytes(), length...)
@@ -105,7 +116,7 @@ func SingleSignTransaction(transaction *types.Transaction, group []int, witnessA
 
 	if len(group) > 1 {
 		for i := 1; i < len(group); i++ {
-			var data []byte
+			data := transaction.Witnesses[group[i]]
 			length := make([]byte, 8)
 			binary.LittleEndian.PutUint64(length, uint64(len(data)))
 			message = append(message, length...)
@@ -113,12 +124,30 @@ func SingleSignTransaction(transaction *types.Transaction, group []int, witnessA
 		}
 	}
 
-	message, err = blake2b.Blake256(message)
+	return blake2b.Blake256(message)
+}
+
+// signFunc signs the 32 bytes message and returns the 65 bytes recoverable signature
+type signFunc func(message []byte) ([]byte, error)
+
+func SingleSignTransaction(transaction *types.Transaction, group []int, witnessArgs *types.WitnessArgs, key crypto.Key) error {
+	return singleSign(transaction, group, witnessArgs, key.Sign)
+}
+
+// SingleSignTransactionWithSigner is SingleSignTransaction by a signer which keeps the private key, e.g. a hardware wallet
+func SingleSignTransactionWithSigner(ctx context.Context, transaction *types.Transaction, group []int, witnessArgs *types.WitnessArgs, signer crypto.Signer) error {
+	return singleSign(transaction, group, witnessArgs, func(message []byte) ([]byte, error) {
+		return signer.SignMessage(ctx, message)
+	})
+}
+
+func singleSign(transaction *types.Transaction, group []int, witnessArgs *types.WitnessArgs, sign signFunc) error {
+	message, err := groupSigningMessage(transaction, group, witnessArgs)
 	if err != nil {
 		return err
 	}
 
-	signed, err := key.Sign(message)
+	signed, err := sign(message)
 	if err != nil {
 		return err
 	}
@@ -139,45 +168,40 @@ func SingleSignTransaction(transaction *types.Transaction, group []int, witnessA
 }
 
 func MultiSignTransaction(transaction *types.Transaction, group []int, witnessArgs *types.WitnessArgs, serialize []byte, keys ...crypto.Key) error {
-	var emptySignature []byte
-	for range keys {
-		emptySignature = append(emptySignature, SignaturePlaceholder...)
+	signs := make([]signFunc, len(keys))
+	for i, key := range keys {
+		signs[i] = key.Sign
 	}
-	witnessArgs.Lock = append(serialize, emptySignature...)
-
-	data, err := witnessArgs.Serialize()
-	if err != nil {
-		return err
-	}
-	length := make([]byte, 8)
-	binary.LittleEndian.PutUint64(length, uint64(len(data)))
+	return multiSign(transaction, group, witnessArgs, serialize, signs)
+}
 
-	hash, err := transaction.ComputeHash()
-	if err != nil {
-		return err
+// MultiSignTransactionWithSigners is MultiSignTransaction by signers which keep the private keys
+func MultiSignTransactionWithSigners(ctx context.Context, transaction *types.Transaction, group []int, witnessArgs *types.WitnessArgs, serialize []byte, signers ...crypto.Signer) error {
+	signs := make([]signFunc, len(signers))
+	for i := range signers {
+		signer := signers[i]
+		signs[i] = func(message []byte) ([]byte, error) {
+			return signer.SignMessage(ctx, message)
+		}
 	}
+	return multiSign(transaction, group, witnessArgs, serialize, signs)
+}
 
-	message := append(hash.Bytes(), length...)
-	message = append(message, data...)
-
-	if len(group) > 1 {
-		for i := 1; i < len(group); i++ {
-			var data []byte
-			length := make([]byte, 8)
-			binary.LittleEndian.PutUint64(length, uint64(len(data)))
-			message = append(message, length...)
-			message = append(message, data...)
-		}
+func multiSign(transaction *types.Transaction, group []int, witnessArgs *types.WitnessArgs, serialize []byte, signs []signFunc) error {
+	var emptySignature []byte
+	for range signs {
+		emptySignature = append(emptySignature, SignaturePlaceholder...)
 	}
+	witnessArgs.Lock = append(serialize, emptySignature...)
 
-	message, err = blake2b.Blake256(message)
+	message, err := groupSigningMessage(transaction, group, witnessArgs)
 	if err != nil {
 		return err
 	}
 
 	var signed []byte
-	for _, key := range keys {
-		s, err := key.Sign(message)
+	for _, sign := range signs {
+		s, err := sign(message)
 		if err != nil {
 			return err
 		}
@@ -216,7 +240,7 @@ func SingleSegmentSignMessage(transaction *types.Transaction, start int, end int
 	message = append(message, data...)
 
 	for i := start + 1; i < end; i++ {
-		var data []byte
+		data := transaction.Witnesses[i]
 		length := make([]byte, 8)
 		binary.LittleEndian.PutUint64(length, uint64(len(data)))
 		message = append(message, length...)
@@ -0,0 +1,31 @@
+package transaction
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ququzone/ckb-sdk-go/types"
+)
+
+func TestAddInputsForTransactionSince(t *testing.T) {
+	multisig := &types.Script{
+		CodeHash: types.HexToHash(SECP256K1_BLAKE160_MULTISIG_ALL_TYPE_HASH),
+		HashType: types.HashTypeType,
+		Args:     common.FromHex("0xf04cec84bc37f683613bed2f242c9aa1b678e9fee803000000000020"),
+	}
+	single := &types.Script{
+		CodeHash: types.HexToHash(SECP256K1_BLAKE160_SIGHASH_ALL_TYPE_HASH),
+		HashType: types.HashTypeType,
+		Args:     common.FromHex("0xedcda9513fa030ce4308e29245a22c022d0443bb"),
+	}
+	tx := &types.Transaction{}
+	_, _, err := AddInputsForTransaction(tx, []*types.Cell{
+		{Lock: multisig, OutPoint: &types.OutPoint{TxHash: types.HexToHash("0x0a"), Index: 0}},
+		{Lock: single, OutPoint: &types.OutPoint{TxHash: types.HexToHash("0x0b"), Index: 0}},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0x20000000000003e8), tx.Inputs[0].Since)
+	assert.Equal(t, uint64(0), tx.Inputs[1].Since)
+}
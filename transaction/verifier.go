@@ -0,0 +1,198 @@
+package transaction
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ququzone/ckb-sdk-go/types"
+)
+
+const (
+	// MaxBlockBytes is the consensus limit of serialized block size, a transaction can not be larger.
+	MaxBlockBytes uint64 = 597000
+)
+
+type VerifyErrorKind string
+
+// Kinds are named after the node side errors of the same checks
+const (
+	VerifyErrorEmpty                     VerifyErrorKind = "Empty"
+	VerifyErrorMismatchedVersion         VerifyErrorKind = "MismatchedVersion"
+	VerifyErrorOutputsDataLengthMismatch VerifyErrorKind = "OutputsDataLengthMismatch"
+	VerifyErrorInsufficientCellCapacity  VerifyErrorKind = "InsufficientCellCapacity"
+	VerifyErrorOutputsSumOverflow        VerifyErrorKind = "OutputsSumOverflow"
+	VerifyErrorCapacityOverflow          VerifyErrorKind = "CapacityOverflow"
+	VerifyErrorUnresolvedInputs          VerifyErrorKind = "UnresolvedInputs"
+	VerifyErrorDuplicateInputs           VerifyErrorKind = "DuplicateInputs"
+	VerifyErrorDuplicateCellDeps         VerifyErrorKind = "DuplicateCellDeps"
+	VerifyErrorDuplicateHeaderDeps       VerifyErrorKind = "DuplicateHeaderDeps"
+	VerifyErrorInvalidSince              VerifyErrorKind = "InvalidSince"
+	VerifyErrorMissingWitnesses          VerifyErrorKind = "MissingWitnesses"
+	VerifyErrorExceededMaximumBlockBytes VerifyErrorKind = "ExceededMaximumBlockBytes"
+	// VerifyErrorMissingOutPoint has no node side equivalent, the transaction can not even be serialized
+	VerifyErrorMissingOutPoint VerifyErrorKind = "MissingOutPoint"
+)
+
+// VerifyError is one violation found by Verify.
+// Index is the offending input, output or dep, -1 when it is about the whole transaction.
+type VerifyError struct {
+	Kind    VerifyErrorKind
+	Index   int
+	Message string
+}
+
+func (e *VerifyError) Error() string {
+	if e.Index < 0 {
+		return fmt.Sprintf("%s: %s", e.Kind, e.Message)
+	}
+	return fmt.Sprintf("%s(%d): %s", e.Kind, e.Index, e.Message)
+}
+
+// VerifyErrors are all violations of a transaction
+type VerifyErrors []*VerifyError
+
+func (e VerifyErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Has reports whether a violation of kind was found
+func (e VerifyErrors) Has(kind VerifyErrorKind) bool {
+	for _, err := range e {
+		if err.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Verify check transaction offline before sending it, the checks mirror those done by node.
+// inputs are the cell outputs spent by tx.Inputs in the same order.
+// Outputs sum is required not to exceed inputs sum, so Nervos DAO withdraw transactions will
+// report OutputsSumOverflow which callers should ignore.
+// Returns nil or VerifyErrors.
+func Verify(tx *types.Transaction, inputs []*types.CellOutput) error {
+	var errs VerifyErrors
+	add := func(kind VerifyErrorKind, index int, format string, args ...interface{}) {
+		errs = append(errs, &VerifyError{Kind: kind, Index: index, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if tx.Version != 0 {
+		add(VerifyErrorMismatchedVersion, -1, "version %d is not supported", tx.Version)
+	}
+
+	if len(tx.Inputs) == 0 {
+		add(VerifyErrorEmpty, -1, "inputs are empty")
+	}
+	if len(tx.Outputs) == 0 {
+		add(VerifyErrorEmpty, -1, "outputs are empty")
+	}
+
+	if len(tx.OutputsData) != len(tx.Outputs) {
+		add(VerifyErrorOutputsDataLengthMismatch, -1, "%d outputs but %d outputs data", len(tx.Outputs), len(tx.OutputsData))
+	}
+
+	if len(tx.Witnesses) < len(tx.Inputs) {
+		add(VerifyErrorMissingWitnesses, -1, "%d inputs but %d witnesses", len(tx.Inputs), len(tx.Witnesses))
+	}
+
+	var outputsCapacity uint64
+	outputsOverflow := false
+	for i, output := range tx.Outputs {
+		if i < len(tx.OutputsData) {
+			occupied := output.OccupiedCapacity(tx.OutputsData[i])
+			if output.Capacity < occupied {
+				add(VerifyErrorInsufficientCellCapacity, i, "capacity %d is less than occupied capacity %d", output.Capacity, occupied)
+			}
+		}
+		if outputsCapacity+output.Capacity < outputsCapacity {
+			outputsOverflow = true
+		}
+		outputsCapacity += output.Capacity
+	}
+	if outputsOverflow {
+		add(VerifyErrorCapacityOverflow, -1, "outputs capacity overflow")
+	}
+
+	if len(inputs) != len(tx.Inputs) {
+		add(VerifyErrorUnresolvedInputs, -1, "%d inputs but %d resolved inputs", len(tx.Inputs), len(inputs))
+	} else if !outputsOverflow {
+		var inputsCapacity uint64
+		inputsOverflow := false
+		for _, input := range inputs {
+			if inputsCapacity+input.Capacity < inputsCapacity {
+				inputsOverflow = true
+			}
+			inputsCapacity += input.Capacity
+		}
+		if inputsOverflow {
+			add(VerifyErrorCapacityOverflow, -1, "inputs capacity overflow")
+		} else if outputsCapacity > inputsCapacity {
+			add(VerifyErrorOutputsSumOverflow, -1, "outputs capacity %d exceeds inputs capacity %d", outputsCapacity, inputsCapacity)
+		}
+	}
+
+	missingOutPoint := false
+	spent := make(map[types.OutPoint]bool)
+	for i, input := range tx.Inputs {
+		if input == nil || input.PreviousOutput == nil {
+			add(VerifyErrorMissingOutPoint, i, "input has no previous output")
+			missingOutPoint = true
+			continue
+		}
+		if spent[*input.PreviousOutput] {
+			add(VerifyErrorDuplicateInputs, i, "%s:%d is spent more than once", input.PreviousOutput.TxHash, input.PreviousOutput.Index)
+		}
+		spent[*input.PreviousOutput] = true
+
+		if _, err := types.ParseSince(input.Since); err != nil {
+			add(VerifyErrorInvalidSince, i, "since %#x is malformed", input.Since)
+		}
+	}
+
+	type dep struct {
+		outPoint types.OutPoint
+		depType  types.DepType
+	}
+	deps := make(map[dep]bool)
+	for i, cellDep := range tx.CellDeps {
+		if cellDep == nil || cellDep.OutPoint == nil {
+			add(VerifyErrorMissingOutPoint, i, "cell dep has no out point")
+			missingOutPoint = true
+			continue
+		}
+		key := dep{outPoint: *cellDep.OutPoint, depType: cellDep.DepType}
+		if deps[key] {
+			add(VerifyErrorDuplicateCellDeps, i, "%s:%d is duplicated", cellDep.OutPoint.TxHash, cellDep.OutPoint.Index)
+		}
+		deps[key] = true
+	}
+
+	headerDeps := make(map[types.Hash]bool)
+	for i, hash := range tx.HeaderDeps {
+		if headerDeps[hash] {
+			add(VerifyErrorDuplicateHeaderDeps, i, "%s is duplicated", hash)
+		}
+		headerDeps[hash] = true
+	}
+
+	if missingOutPoint {
+		return errs
+	}
+	data, err := tx.SerializeWithWitnesses()
+	if err != nil {
+		return err
+	}
+	// Serialized in block, a transaction takes 4 more bytes for its offset
+	if size := uint64(len(data)) + 4; size > MaxBlockBytes {
+		add(VerifyErrorExceededMaximumBlockBytes, -1, "size %d exceeds %d", size, MaxBlockBytes)
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
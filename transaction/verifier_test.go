@@ -0,0 +1,107 @@
+package transaction
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ququzone/ckb-sdk-go/types"
+)
+
+func testLock() *types.Script {
+	return &types.Script{
+		CodeHash: types.HexToHash(SECP256K1_BLAKE160_SIGHASH_ALL_TYPE_HASH),
+		HashType: types.HashTypeType,
+		Args:     common.FromHex("0xedcda9513fa030ce4308e29245a22c022d0443bb"),
+	}
+}
+
+func testVerifyTx() (*types.Transaction, []*types.CellOutput) {
+	tx := &types.Transaction{
+		Version:    0,
+		HeaderDeps: []types.Hash{},
+		CellDeps: []*types.CellDep{
+			{
+				OutPoint: &types.OutPoint{TxHash: types.HexToHash("0x71a7ba8fc96349fea0ed3a5c47992e3b4084b031a42264a018e0072e8172e46c"), Index: 0},
+				DepType:  types.DepTypeDepGroup,
+			},
+		},
+		Inputs: []*types.CellInput{
+			{PreviousOutput: &types.OutPoint{TxHash: types.HexToHash("0x01"), Index: 0}},
+			{PreviousOutput: &types.OutPoint{TxHash: types.HexToHash("0x01"), Index: 1}},
+		},
+		Outputs: []*types.CellOutput{
+			{Capacity: 10000000000, Lock: testLock()},
+			{Capacity: 6100000000, Lock: testLock()},
+		},
+		OutputsData: [][]byte{{}, {}},
+		Witnesses:   [][]byte{EmptyWitnessArgPlaceholder, {}},
+	}
+	inputs := []*types.CellOutput{
+		{Capacity: 10000000000, Lock: testLock()},
+		{Capacity: 6200000000, Lock: testLock()},
+	}
+	return tx, inputs
+}
+
+func TestVerify(t *testing.T) {
+	tx, inputs := testVerifyTx()
+	assert.Nil(t, Verify(tx, inputs))
+}
+
+func TestVerifyViolations(t *testing.T) {
+	tx, inputs := testVerifyTx()
+	tx.Outputs[1].Capacity = 6099999999
+	tx.Outputs[0].Capacity = 10200000000
+	tx.Inputs[1].PreviousOutput = tx.Inputs[0].PreviousOutput
+	tx.Inputs[0].Since = 0x6000000000000000
+	tx.CellDeps = append(tx.CellDeps, tx.CellDeps[0])
+	tx.HeaderDeps = []types.Hash{types.HexToHash("0x01"), types.HexToHash("0x01")}
+	tx.OutputsData = append(tx.OutputsData, []byte{})
+	tx.Witnesses = tx.Witnesses[:1]
+
+	err := Verify(tx, inputs)
+	errs, ok := err.(VerifyErrors)
+	assert.True(t, ok)
+	for _, kind := range []VerifyErrorKind{
+		VerifyErrorInsufficientCellCapacity,
+		VerifyErrorOutputsSumOverflow,
+		VerifyErrorDuplicateInputs,
+		VerifyErrorInvalidSince,
+		VerifyErrorDuplicateCellDeps,
+		VerifyErrorDuplicateHeaderDeps,
+		VerifyErrorOutputsDataLengthMismatch,
+		VerifyErrorMissingWitnesses,
+	} {
+		assert.True(t, errs.Has(kind), string(kind))
+	}
+	for _, e := range errs {
+		if e.Kind == VerifyErrorInsufficientCellCapacity {
+			assert.Equal(t, 1, e.Index)
+		}
+	}
+	assert.False(t, errs.Has(VerifyErrorExceededMaximumBlockBytes))
+
+	tx, inputs = testVerifyTx()
+	tx.OutputsData[0] = make([]byte, MaxBlockBytes)
+	tx.Outputs[0].Capacity = 18446744073709551615
+	errs = Verify(tx, inputs).(VerifyErrors)
+	assert.True(t, errs.Has(VerifyErrorExceededMaximumBlockBytes))
+	assert.True(t, errs.Has(VerifyErrorCapacityOverflow))
+
+	tx, _ = testVerifyTx()
+	errs = Verify(tx, nil).(VerifyErrors)
+	assert.True(t, errs.Has(VerifyErrorUnresolvedInputs))
+}
+
+func TestVerifyMissingOutPoint(t *testing.T) {
+	tx, inputs := testVerifyTx()
+	tx.Inputs[1].PreviousOutput = nil
+	tx.CellDeps[0].OutPoint = nil
+	errs, ok := Verify(tx, inputs).(VerifyErrors)
+	assert.True(t, ok)
+	assert.Equal(t, 2, len(errs))
+	assert.Equal(t, &VerifyError{Kind: VerifyErrorMissingOutPoint, Index: 1, Message: "input has no previous output"}, errs[0])
+	assert.Equal(t, &VerifyError{Kind: VerifyErrorMissingOutPoint, Index: 0, Message: "cell dep has no out point"}, errs[1])
+}
@@ -0,0 +1,185 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ququzone/ckb-sdk-go/crypto/blake2b"
+)
+
+var (
+	ErrHeaderHashMismatch       = errors.New("header hash mismatch")
+	ErrTransactionHashMismatch  = errors.New("transaction hash mismatch")
+	ErrTransactionsRootMismatch = errors.New("transactions root mismatch")
+	ErrProposalsHashMismatch    = errors.New("proposals hash mismatch")
+	ErrUnclesHashMismatch       = errors.New("uncles hash mismatch")
+)
+
+// MerkleRoot compute root of the complete binary merkle tree of leaves, empty leaves return zero hash.
+// Nodes are stored in an array where node i has children 2i+1 and 2i+2, leaves are the last len(leaves) nodes.
+func MerkleRoot(leaves []Hash) (Hash, error) {
+	if len(leaves) == 0 {
+		return Hash{}, nil
+	}
+
+	nodes := make([]Hash, 2*len(leaves)-1)
+	copy(nodes[len(leaves)-1:], leaves)
+	for i := len(leaves) - 2; i >= 0; i-- {
+		merged, err := blake2b.Blake256(append(nodes[2*i+1].Bytes(), nodes[2*i+2].Bytes()...))
+		if err != nil {
+			return Hash{}, err
+		}
+		nodes[i] = BytesToHash(merged)
+	}
+
+	return nodes[0], nil
+}
+
+// ComputeTransactionsRoot compute the transactions root committed in header,
+// which is the merkle root of transaction hashes root and witness hashes root.
+func (b *Block) ComputeTransactionsRoot() (Hash, error) {
+	txHashes := make([]Hash, len(b.Transactions))
+	witnessHashes := make([]Hash, len(b.Transactions))
+	for i, tx := range b.Transactions {
+		hash, err := tx.ComputeHash()
+		if err != nil {
+			return Hash{}, err
+		}
+		txHashes[i] = hash
+
+		hash, err = tx.ComputeWitnessHash()
+		if err != nil {
+			return Hash{}, err
+		}
+		witnessHashes[i] = hash
+	}
+
+	txRoot, err := MerkleRoot(txHashes)
+	if err != nil {
+		return Hash{}, err
+	}
+	witnessRoot, err := MerkleRoot(witnessHashes)
+	if err != nil {
+		return Hash{}, err
+	}
+
+	return MerkleRoot([]Hash{txRoot, witnessRoot})
+}
+
+// ComputeProposalsHash compute the proposals hash committed in header, zero hash if no proposals
+func (b *Block) ComputeProposalsHash() (Hash, error) {
+	return computeProposalsHash(b.Proposals)
+}
+
+// ComputeUnclesHash compute the uncles hash committed in header, zero hash if no uncles
+func (b *Block) ComputeUnclesHash() (Hash, error) {
+	if len(b.Uncles) == 0 {
+		return Hash{}, nil
+	}
+
+	var data []byte
+	for _, uncle := range b.Uncles {
+		hash, err := uncle.Header.ComputeHash()
+		if err != nil {
+			return Hash{}, err
+		}
+		data = append(data, hash.Bytes()...)
+	}
+
+	hash, err := blake2b.Blake256(data)
+	if err != nil {
+		return Hash{}, err
+	}
+
+	return BytesToHash(hash), nil
+}
+
+// Verify check the header hash and that header commitments match the block body.
+func (b *Block) Verify() error {
+	if err := verifyHeader(b.Header); err != nil {
+		return err
+	}
+
+	for i, tx := range b.Transactions {
+		hash, err := tx.ComputeHash()
+		if err != nil {
+			return err
+		}
+		if hash != tx.Hash {
+			return fmt.Errorf("%w: transaction %d, expected %s, actual %s", ErrTransactionHashMismatch, i, tx.Hash, hash)
+		}
+	}
+
+	root, err := b.ComputeTransactionsRoot()
+	if err != nil {
+		return err
+	}
+	if root != b.Header.TransactionsRoot {
+		return ErrTransactionsRootMismatch
+	}
+
+	hash, err := b.ComputeProposalsHash()
+	if err != nil {
+		return err
+	}
+	if hash != b.Header.ProposalsHash {
+		return ErrProposalsHashMismatch
+	}
+
+	for _, uncle := range b.Uncles {
+		if err := verifyHeader(uncle.Header); err != nil {
+			return err
+		}
+		hash, err := computeProposalsHash(uncle.Proposals)
+		if err != nil {
+			return err
+		}
+		if hash != uncle.Header.ProposalsHash {
+			return ErrProposalsHashMismatch
+		}
+	}
+
+	hash, err = b.ComputeUnclesHash()
+	if err != nil {
+		return err
+	}
+	if hash != b.Header.UnclesHash {
+		return ErrUnclesHashMismatch
+	}
+
+	return nil
+}
+
+func verifyHeader(header *Header) error {
+	hash, err := header.ComputeHash()
+	if err != nil {
+		return err
+	}
+	if hash != header.Hash {
+		return fmt.Errorf("%w: block %d, expected %s, actual %s", ErrHeaderHashMismatch, header.Number, header.Hash, hash)
+	}
+	return nil
+}
+
+func computeProposalsHash(proposals []string) (Hash, error) {
+	if len(proposals) == 0 {
+		return Hash{}, nil
+	}
+
+	ids, err := decodeProposals(proposals)
+	if err != nil {
+		return Hash{}, err
+	}
+
+	var data []byte
+	for _, id := range ids {
+		data = append(data, id...)
+	}
+
+	hash, err := blake2b.Blake256(data)
+	if err != nil {
+		return Hash{}, err
+	}
+
+	return BytesToHash(hash), nil
+}
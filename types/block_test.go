@@ -0,0 +1,246 @@
+package types
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerkleRoot(t *testing.T) {
+	a := HexToHash("0x01")
+	b := HexToHash("0x02")
+	c := HexToHash("0x03")
+
+	root, err := MerkleRoot(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, Hash{}, root)
+
+	root, err = MerkleRoot([]Hash{a})
+	assert.Nil(t, err)
+	assert.Equal(t, a, root)
+
+	root, err = MerkleRoot([]Hash{a, b})
+	assert.Nil(t, err)
+	assert.Equal(t, HexToHash("0x58dec85aba0ad66ef137c232d88df1b04a4c7917b09a59f158d8120ad1511fbe"), root)
+
+	// the unbalanced tree merges b and c first
+	root, err = MerkleRoot([]Hash{a, b, c})
+	assert.Nil(t, err)
+	assert.Equal(t, HexToHash("0x664c13c07f6f4c7f31c451b011efe02d7ae42a6c958e18307c51e20bf03bc116"), root)
+}
+
+func TestHeaderSerialize(t *testing.T) {
+	header := &Header{
+		Version:       0,
+		CompactTarget: 0x1a08a97e,
+		Nonce:         new(big.Int).SetUint64(0x0102),
+		Number:        1,
+	}
+
+	data, err := header.Serialize()
+	assert.Nil(t, err)
+	assert.Equal(t, 208, len(data))
+	assert.Equal(t, []byte{0x02, 0x01, 0x00}, data[192:195])
+	hash, err := header.ComputeHash()
+	assert.Nil(t, err)
+	assert.Equal(t, HexToHash("0x65f4496d2c118b95a18616f7710d657b9268368a918ece1f8ab9c27b9d659737"), hash)
+
+	header.Nonce = new(big.Int).Lsh(big.NewInt(1), 128)
+	_, err = header.Serialize()
+	assert.Error(t, err)
+}
+
+// testBlock is a block with a cellbase, two transactions, proposals and two uncles.
+// Hashes and roots are computed from the molecule schema and CBMT definition by a separate implementation,
+// so they don't depend on the code under test.
+func testBlock() *Block {
+	lock := &Script{
+		CodeHash: HexToHash("0x9bd7e06f3ecf4be0f2fcd2188b23f1b9fcc88e5d4b65a8637b17723bbda3cce8"),
+		HashType: HashTypeType,
+		Args:     common.FromHex("0xedcda9513fa030ce4308e29245a22c022d0443bb"),
+	}
+	cellbase := &Transaction{
+		Version:    0,
+		Hash:       HexToHash("0x3a5406af9a2622db957eb1ff25f8f752fe3c9a8ee36645dbfe17f71cc27f80bc"),
+		CellDeps:   []*CellDep{},
+		HeaderDeps: []Hash{},
+		Inputs: []*CellInput{
+			{
+				Since:          11068,
+				PreviousOutput: &OutPoint{TxHash: Hash{}, Index: 0xffffffff},
+			},
+		},
+		Outputs:     []*CellOutput{{Capacity: 104000000000, Lock: lock}},
+		OutputsData: [][]byte{{}},
+		// CellbaseWitness of lock and message 0x0102
+		Witnesses: [][]byte{common.FromHex("0x5b0000000c00000055000000490000001000000030000000310000009bd7e06f3ecf4be0f2fcd2188b23f1b9fcc88e5d4b65a8637b17723bbda3cce80114000000edcda9513fa030ce4308e29245a22c022d0443bb020000000102")},
+	}
+
+	tx := testTransaction()
+	tx.Hash = HexToHash("0x867fcee9a870975a161b0d3cb9d43935bd7936e39a23333b5b38c2c864199e7a")
+
+	transfer := &Transaction{
+		Version: 0,
+		Hash:    HexToHash("0x0b094efa4bb2f8f444910e9a5ae448dd15955d93ee1347088a16fa100f49444b"),
+		CellDeps: []*CellDep{
+			{
+				OutPoint: &OutPoint{TxHash: HexToHash("0x71a7ba8fc96349fea0ed3a5c47992e3b4084b031a42264a018e0072e8172e46c"), Index: 0},
+				DepType:  DepTypeDepGroup,
+			},
+		},
+		HeaderDeps: []Hash{},
+		Inputs: []*CellInput{
+			{
+				Since:          0,
+				PreviousOutput: &OutPoint{TxHash: HexToHash("0xa563884b3686078ec7e7677a5f86449b15cf2693f3c1241766c6996f206cc541"), Index: 0},
+			},
+		},
+		Outputs: []*CellOutput{
+			{
+				Capacity: 20000000000,
+				Lock: &Script{
+					CodeHash: HexToHash("0x9bd7e06f3ecf4be0f2fcd2188b23f1b9fcc88e5d4b65a8637b17723bbda3cce8"),
+					HashType: HashTypeType,
+					Args:     common.FromHex("0x36c329ed630d6ce750712a477543672adab57f4c"),
+				},
+			},
+		},
+		OutputsData: [][]byte{{}},
+		Witnesses:   [][]byte{common.FromHex("0x55000000100000005500000055000000410000001111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111")},
+	}
+
+	dao := HexToHash("0x8874337e541ea12e0000c16ff286230029bfa3320800000000710b00c0fefe06")
+	epoch := uint64(1800<<40 | 24<<24 | 6)
+	return &Block{
+		Header: &Header{
+			Version:          0,
+			CompactTarget:    0x1a08a97e,
+			Timestamp:        0x16e70e6985c,
+			Number:           11068,
+			Epoch:            epoch,
+			ParentHash:       HexToHash("0xa0c1a1a39a2e8e4e2bdb1f2fcbd4a8ce1e48b74a2fc2f6b9f10b9e1fd8d1f0e3"),
+			TransactionsRoot: HexToHash("0x1c6334560fb4028796c5f037bf0d537f5bd94d9d2d3ab82da258ba0ee34af482"),
+			ProposalsHash:    HexToHash("0xba617ffac999790fd18b0fd838cb2a9dd85beba8c5c17536e760564aa2d9e56d"),
+			UnclesHash:       HexToHash("0xa9857e3b675adb681f325d2e43598ba1d38516e32f2d188d95d174ebefc46f11"),
+			Dao:              dao,
+			Nonce:            new(big.Int).SetBytes(common.FromHex("0x0102030405060708090a0b0c0d0e0f10")),
+			Hash:             HexToHash("0x1b9680c4d281665768d726160f8bb49c0bd389b9064a669ccb9d820f8579a1e5"),
+		},
+		Proposals:    []string{"0x0102030405060708090a", "0xa0a1a2a3a4a5a6a7a8a9"},
+		Transactions: []*Transaction{cellbase, tx, transfer},
+		Uncles: []*UncleBlock{
+			{
+				Header: &Header{
+					Version:          0,
+					CompactTarget:    0x1a08a97e,
+					Timestamp:        0x16e70e6985c - 8000,
+					Number:           11067,
+					Epoch:            epoch,
+					ParentHash:       HexToHash("0x1111111111111111111111111111111111111111111111111111111111111111"),
+					TransactionsRoot: HexToHash("0x2222222222222222222222222222222222222222222222222222222222222222"),
+					ProposalsHash:    HexToHash("0xb61cce76dfec6daa56c200efa7996d381f7d9820721f9ee8e6016aa4b2b44033"),
+					Dao:              dao,
+					Nonce:            big.NewInt(0x1234),
+					Hash:             HexToHash("0x54d047c6f6845ee20d9bd2ce3ae5cbdfd7b1579a531941225d147d473c320e2a"),
+				},
+				Proposals: []string{"0xb0b1b2b3b4b5b6b7b8b9"},
+			},
+			{
+				Header: &Header{
+					Version:          0,
+					CompactTarget:    0x1a08a97e,
+					Timestamp:        0x16e70e6985c - 4000,
+					Number:           11066,
+					Epoch:            epoch,
+					ParentHash:       HexToHash("0x3333333333333333333333333333333333333333333333333333333333333333"),
+					TransactionsRoot: HexToHash("0x4444444444444444444444444444444444444444444444444444444444444444"),
+					Dao:              dao,
+					Nonce:            big.NewInt(0x5678),
+					Hash:             HexToHash("0xf8a7d947396000f845cc05ca9f9b64dd39736c387cf52aede2345bf07ef5fe64"),
+				},
+				Proposals: []string{},
+			},
+		},
+	}
+}
+
+func TestBlockCommitments(t *testing.T) {
+	block := testBlock()
+
+	hash, err := block.Header.ComputeHash()
+	assert.Nil(t, err)
+	assert.Equal(t, block.Header.Hash, hash)
+
+	witnessHashes := []string{
+		"0x348f85896cdbb20defe3e5438294bce40e1c1c9caec81ce74f20c0611daddb4b",
+		"0x38acc048c05230d7b2cefd5af4f4bdbf3cfcf934e09b2d53acbecb4845375f1c",
+		"0x1a76c4c3fc72ecd8105575fe34a657aa78f8a9fae799b08f31da613b9b5ccda5",
+	}
+	txHashes := make([]Hash, len(block.Transactions))
+	for i, tx := range block.Transactions {
+		hash, err = tx.ComputeHash()
+		assert.Nil(t, err)
+		assert.Equal(t, tx.Hash, hash)
+		txHashes[i] = hash
+		hash, err = tx.ComputeWitnessHash()
+		assert.Nil(t, err)
+		assert.Equal(t, HexToHash(witnessHashes[i]), hash)
+	}
+	root, err := MerkleRoot(txHashes)
+	assert.Nil(t, err)
+	assert.Equal(t, HexToHash("0xf233715afd928bda96518babe1de200ec3ddf73449f3f4cb715ba1b1c7d30951"), root)
+
+	// transactions root commits to both the transaction hashes root and the witness hashes root
+	hash, err = block.ComputeTransactionsRoot()
+	assert.Nil(t, err)
+	assert.Equal(t, block.Header.TransactionsRoot, hash)
+
+	hash, err = block.ComputeProposalsHash()
+	assert.Nil(t, err)
+	assert.Equal(t, block.Header.ProposalsHash, hash)
+
+	hash, err = block.ComputeUnclesHash()
+	assert.Nil(t, err)
+	assert.Equal(t, block.Header.UnclesHash, hash)
+
+	for _, uncle := range block.Uncles {
+		hash, err = uncle.Header.ComputeHash()
+		assert.Nil(t, err)
+		assert.Equal(t, uncle.Header.Hash, hash)
+	}
+}
+
+func TestBlockVerify(t *testing.T) {
+	block := testBlock()
+	assert.Nil(t, block.Verify())
+
+	_, err := block.Serialize()
+	assert.Nil(t, err)
+
+	block = testBlock()
+	block.Header.Timestamp = 1
+	assert.True(t, errors.Is(block.Verify(), ErrHeaderHashMismatch))
+
+	block = testBlock()
+	block.Transactions[0].Witnesses = [][]byte{}
+	assert.True(t, errors.Is(block.Verify(), ErrTransactionsRootMismatch))
+
+	block = testBlock()
+	block.Transactions[0].Version = 1
+	assert.True(t, errors.Is(block.Verify(), ErrTransactionHashMismatch))
+
+	block = testBlock()
+	block.Proposals = block.Proposals[:1]
+	assert.True(t, errors.Is(block.Verify(), ErrProposalsHashMismatch))
+
+	block = testBlock()
+	block.Uncles = nil
+	assert.True(t, errors.Is(block.Verify(), ErrUnclesHashMismatch))
+
+	block = testBlock()
+	block.Proposals = []string{"0x01"}
+	assert.Error(t, block.Verify())
+}
@@ -1,6 +1,8 @@
 package types
 
 import (
+	"errors"
+	"fmt"
 	"math/big"
 
 	"github.com/ququzone/ckb-sdk-go/crypto/blake2b"
@@ -13,6 +15,8 @@ type TransactionStatus string
 const (
 	HashTypeData ScriptHashType = "data"
 	HashTypeType ScriptHashType = "type"
+	// HashTypeData1 matches code by data hash and runs it in the CKB2021 vm version 1
+	HashTypeData1 ScriptHashType = "data1"
 
 	DepTypeCode     DepType = "code"
 	DepTypeDepGroup DepType = "dep_group"
@@ -20,6 +24,15 @@ const (
 	TransactionStatusPending   TransactionStatus = "pending"
 	TransactionStatusProposed  TransactionStatus = "proposed"
 	TransactionStatusCommitted TransactionStatus = "committed"
+	TransactionStatusRejected  TransactionStatus = "rejected"
+	TransactionStatusUnknown   TransactionStatus = "unknown"
+
+	// OneCKB is the shannons of one CKB, a cell occupies one CKB for each byte
+	OneCKB uint64 = 100000000
+)
+
+var (
+	ErrInsufficientCellCapacity = errors.New("insufficient cell capacity")
 )
 
 type Epoch struct {
@@ -44,6 +57,20 @@ type Header struct {
 	Version          uint     `json:"version"`
 }
 
+func (h *Header) ComputeHash() (Hash, error) {
+	data, err := h.Serialize()
+	if err != nil {
+		return Hash{}, err
+	}
+
+	hash, err := blake2b.Blake256(data)
+	if err != nil {
+		return Hash{}, err
+	}
+
+	return BytesToHash(hash), nil
+}
+
 type OutPoint struct {
 	TxHash Hash `json:"tx_hash"`
 	Index  uint `json:"index"`
@@ -84,6 +111,11 @@ func (script *Script) Equals(obj *Script) bool {
 	return sh.String() == oh.String()
 }
 
+// OccupiedCapacity returns shannons occupied by code hash, hash type and args of the script
+func (script *Script) OccupiedCapacity() uint64 {
+	return (HashLength + 1 + uint64(len(script.Args))) * OneCKB
+}
+
 type CellInput struct {
 	Since          uint64    `json:"since"`
 	PreviousOutput *OutPoint `json:"previous_output"`
@@ -95,6 +127,27 @@ type CellOutput struct {
 	Type     *Script `json:"type"`
 }
 
+// OccupiedCapacity returns the minimal shannons the cell must hold with data: capacity field, lock, type and data
+func (o *CellOutput) OccupiedCapacity(data []byte) uint64 {
+	occupied := (8 + uint64(len(data))) * OneCKB
+	if o.Lock != nil {
+		occupied += o.Lock.OccupiedCapacity()
+	}
+	if o.Type != nil {
+		occupied += o.Type.OccupiedCapacity()
+	}
+	return occupied
+}
+
+// CheckCapacity returns ErrInsufficientCellCapacity if capacity is less than occupied capacity
+func (o *CellOutput) CheckCapacity(data []byte) error {
+	occupied := o.OccupiedCapacity(data)
+	if o.Capacity < occupied {
+		return fmt.Errorf("%w: capacity %d is less than occupied capacity %d", ErrInsufficientCellCapacity, o.Capacity, occupied)
+	}
+	return nil
+}
+
 type Transaction struct {
 	Version     uint          `json:"version"`
 	Hash        Hash          `json:"hash"`
@@ -120,6 +173,84 @@ func (t *Transaction) ComputeHash() (Hash, error) {
 	return BytesToHash(hash), nil
 }
 
+// Clone returns a deep copy of the transaction, changing the copy does not affect the original
+func (t *Transaction) Clone() *Transaction {
+	c := *t
+	if t.CellDeps != nil {
+		c.CellDeps = make([]*CellDep, len(t.CellDeps))
+		for i, dep := range t.CellDeps {
+			c.CellDeps[i] = &CellDep{OutPoint: dep.OutPoint.clone(), DepType: dep.DepType}
+		}
+	}
+	if t.HeaderDeps != nil {
+		c.HeaderDeps = append([]Hash{}, t.HeaderDeps...)
+	}
+	if t.Inputs != nil {
+		c.Inputs = make([]*CellInput, len(t.Inputs))
+		for i, input := range t.Inputs {
+			c.Inputs[i] = &CellInput{Since: input.Since, PreviousOutput: input.PreviousOutput.clone()}
+		}
+	}
+	if t.Outputs != nil {
+		c.Outputs = make([]*CellOutput, len(t.Outputs))
+		for i, output := range t.Outputs {
+			c.Outputs[i] = &CellOutput{Capacity: output.Capacity, Lock: output.Lock.clone(), Type: output.Type.clone()}
+		}
+	}
+	c.OutputsData = cloneBytesSlice(t.OutputsData)
+	c.Witnesses = cloneBytesSlice(t.Witnesses)
+	return &c
+}
+
+func (o *OutPoint) clone() *OutPoint {
+	if o == nil {
+		return nil
+	}
+	c := *o
+	return &c
+}
+
+func (script *Script) clone() *Script {
+	if script == nil {
+		return nil
+	}
+	return &Script{CodeHash: script.CodeHash, HashType: script.HashType, Args: cloneBytes(script.Args)}
+}
+
+func cloneBytesSlice(data [][]byte) [][]byte {
+	if data == nil {
+		return nil
+	}
+	c := make([][]byte, len(data))
+	for i, b := range data {
+		c[i] = cloneBytes(b)
+	}
+	return c
+}
+
+// cloneBytes keeps nil and empty slices apart
+func cloneBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	return append([]byte{}, b...)
+}
+
+// ComputeWitnessHash compute hash of the transaction together with witnesses
+func (t *Transaction) ComputeWitnessHash() (Hash, error) {
+	data, err := t.SerializeWithWitnesses()
+	if err != nil {
+		return Hash{}, err
+	}
+
+	hash, err := blake2b.Blake256(data)
+	if err != nil {
+		return Hash{}, err
+	}
+
+	return BytesToHash(hash), nil
+}
+
 type WitnessArgs struct {
 	Lock       []byte `json:"lock"`
 	InputType  []byte `json:"input_type"`
@@ -146,6 +277,8 @@ type Cell struct {
 	Type          *Script   `json:"type"`
 	Cellbase      bool      `json:"cellbase,omitempty"`
 	OutputDataLen uint64    `json:"output_data_len,omitempty"`
+	// Data is set by providers returning cell data, nil otherwise
+	Data []byte `json:"data,omitempty"`
 }
 
 type CellData struct {
@@ -166,6 +299,7 @@ type CellWithStatus struct {
 type TxStatus struct {
 	BlockHash *Hash             `json:"block_hash"`
 	Status    TransactionStatus `json:"status"`
+	Reason    string            `json:"reason,omitempty"`
 }
 
 type TransactionWithStatus struct {
@@ -0,0 +1,65 @@
+package types
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOccupiedCapacity(t *testing.T) {
+	lock := &Script{
+		CodeHash: HexToHash("0x9bd7e06f3ecf4be0f2fcd2188b23f1b9fcc88e5d4b65a8637b17723bbda3cce8"),
+		HashType: HashTypeType,
+		Args:     common.FromHex("0xedcda9513fa030ce4308e29245a22c022d0443bb"),
+	}
+	assert.Equal(t, uint64(5300000000), lock.OccupiedCapacity())
+
+	output := &CellOutput{Capacity: 6100000000, Lock: lock}
+	assert.Equal(t, uint64(6100000000), output.OccupiedCapacity(nil))
+	assert.Nil(t, output.CheckCapacity(nil))
+	assert.True(t, errors.Is(output.CheckCapacity([]byte{0x00}), ErrInsufficientCellCapacity))
+
+	dao := &CellOutput{
+		Capacity: 10200000000,
+		Lock:     lock,
+		Type: &Script{
+			CodeHash: HexToHash("0x82d76d1b75fe2fd9a27dfbaa65a039221a380d76c926f378d3f81cf3e7e13f2e"),
+			HashType: HashTypeType,
+			Args:     []byte{},
+		},
+	}
+	assert.Equal(t, uint64(10200000000), dao.OccupiedCapacity(make([]byte, 8)))
+}
+
+func TestTransactionClone(t *testing.T) {
+	tx := &Transaction{
+		Version:    0,
+		CellDeps:   []*CellDep{{OutPoint: &OutPoint{TxHash: HexToHash("0x01"), Index: 0}, DepType: DepTypeDepGroup}},
+		HeaderDeps: []Hash{HexToHash("0x02")},
+		Inputs:     []*CellInput{{Since: 0, PreviousOutput: &OutPoint{TxHash: HexToHash("0x03"), Index: 1}}},
+		Outputs: []*CellOutput{{
+			Capacity: 6100000000,
+			Lock:     &Script{CodeHash: HexToHash("0x04"), HashType: HashTypeType, Args: []byte{0x05}},
+		}},
+		OutputsData: [][]byte{{}},
+		Witnesses:   [][]byte{{0x06}},
+	}
+	c := tx.Clone()
+	assert.Equal(t, tx, c)
+
+	c.CellDeps[0].OutPoint.Index = 1
+	c.HeaderDeps[0] = HexToHash("0x07")
+	c.Inputs[0].PreviousOutput.Index = 2
+	c.Outputs[0].Capacity = 0
+	c.Outputs[0].Lock.Args[0] = 0x08
+	c.Witnesses[0][0] = 0x09
+	assert.Equal(t, uint(0), tx.CellDeps[0].OutPoint.Index)
+	assert.Equal(t, HexToHash("0x02"), tx.HeaderDeps[0])
+	assert.Equal(t, uint(1), tx.Inputs[0].PreviousOutput.Index)
+	assert.Equal(t, uint64(6100000000), tx.Outputs[0].Capacity)
+	assert.Equal(t, []byte{0x05}, tx.Outputs[0].Lock.Args)
+	assert.Equal(t, []byte{0x06}, tx.Witnesses[0])
+	assert.Nil(t, c.Outputs[0].Type)
+}
@@ -0,0 +1,145 @@
+package types
+
+import (
+	"errors"
+)
+
+// Epoch arithmetic treats EpochParams as the rational number Number + Index / Length,
+// an epoch with Length 0 is a whole epoch.
+
+// Normalize returns the epoch with reduced fraction and whole epochs of the fraction carried into Number
+func (ep *EpochParams) Normalize() *EpochParams {
+	index, length := ep.fraction()
+	number := ep.Number + index/length
+	index = index % length
+	if index == 0 {
+		return &EpochParams{Number: number, Index: 0, Length: 1}
+	}
+	divisor := gcd(index, length)
+	return &EpochParams{Number: number, Index: index / divisor, Length: length / divisor}
+}
+
+// Cmp compares ep and other, returns -1, 0 or +1
+func (ep *EpochParams) Cmp(other *EpochParams) int {
+	a := ep.Normalize()
+	b := other.Normalize()
+	if a.Number != b.Number {
+		if a.Number < b.Number {
+			return -1
+		}
+		return 1
+	}
+	left := a.Index * b.Length
+	right := b.Index * a.Length
+	switch {
+	case left < right:
+		return -1
+	case left > right:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Add returns ep + other, use an epoch with Length 0 to add whole epochs
+func (ep *EpochParams) Add(other *EpochParams) *EpochParams {
+	aIndex, aLength := ep.fraction()
+	bIndex, bLength := other.fraction()
+	return (&EpochParams{
+		Number: ep.Number + other.Number,
+		Index:  aIndex*bLength + bIndex*aLength,
+		Length: aLength * bLength,
+	}).Normalize()
+}
+
+// Sub returns ep - other, it fails when other is greater than ep
+func (ep *EpochParams) Sub(other *EpochParams) (*EpochParams, error) {
+	if ep.Cmp(other) < 0 {
+		return nil, errors.New("epoch subtraction underflow")
+	}
+	aIndex, aLength := ep.fraction()
+	bIndex, bLength := other.fraction()
+	number := ep.Number - other.Number
+	left := aIndex * bLength
+	right := bIndex * aLength
+	if left < right {
+		number--
+		left += aLength * bLength
+	}
+	return (&EpochParams{
+		Number: number,
+		Index:  left - right,
+		Length: aLength * bLength,
+	}).Normalize(), nil
+}
+
+// MaxEpochNumber is the greatest epoch number of since and header epoch fields, which hold 24 bits
+const MaxEpochNumber uint64 = 0xffffff
+
+// EarliestEpoch returns the first epoch start + k * step, k >= 0, that satisfies condition.
+// condition must be monotonic: once an epoch satisfies it, all later epochs do, e.g. an absolute epoch since
+// is satisfied by epochs not before it. Candidates are searched by doubling k then bisecting.
+// Returns nil when no candidate up to MaxEpochNumber satisfies condition, or step is zero and start doesn't.
+func EarliestEpoch(start *EpochParams, step *EpochParams, condition func(*EpochParams) bool) *EpochParams {
+	if first := start.stepped(step, 0); condition(first) {
+		return first
+	}
+	if index, _ := step.fraction(); step.Number == 0 && index == 0 {
+		return nil
+	}
+	low, high := uint64(0), uint64(1)
+	for {
+		epoch := start.stepped(step, high)
+		if epoch.Number > MaxEpochNumber {
+			return nil
+		}
+		if condition(epoch) {
+			break
+		}
+		low, high = high, high*2
+	}
+	for high-low > 1 {
+		middle := low + (high-low)/2
+		if condition(start.stepped(step, middle)) {
+			high = middle
+		} else {
+			low = middle
+		}
+	}
+	return start.stepped(step, high)
+}
+
+// NextEpochOfPeriod returns the first epoch base + k * period, k >= 0, that is not before at, or base when
+// period is 0. A DAO withdraw at epoch at is unlockable since NextEpochOfPeriod(deposit, 180, at).
+func NextEpochOfPeriod(base *EpochParams, period uint64, at *EpochParams) *EpochParams {
+	if period == 0 {
+		return base.stepped(&EpochParams{}, 0)
+	}
+	return EarliestEpoch(base, &EpochParams{Number: period}, func(epoch *EpochParams) bool {
+		return epoch.Cmp(at) >= 0
+	})
+}
+
+// stepped returns ep + k * step, the fraction of ep is kept as is when k is 0 or step is whole epochs
+func (ep *EpochParams) stepped(step *EpochParams, k uint64) *EpochParams {
+	index, length := step.fraction()
+	if k == 0 || index == 0 {
+		return &EpochParams{Number: ep.Number + k*step.Number, Index: ep.Index, Length: ep.Length}
+	}
+	return ep.Add((&EpochParams{Number: k * step.Number, Index: k * index, Length: length}).Normalize())
+}
+
+// fraction returns index and length with Length 0 as 0/1
+func (ep *EpochParams) fraction() (uint64, uint64) {
+	if ep.Length == 0 {
+		return 0, 1
+	}
+	return ep.Index, ep.Length
+}
+
+func gcd(a, b uint64) uint64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
@@ -17,6 +17,7 @@ func ParseEpoch(epoch uint64) *EpochParams {
 	}
 }
 
+// Uint64 returns absolute epoch since of ep, see NewAbsoluteEpochSince for a validated one
 func (ep *EpochParams) Uint64() uint64 {
-	return (32 << 56) + (ep.Length << 40) + (ep.Index << 24) + ep.Number
+	return uint64(SinceMetricEpoch)<<sinceMetricShift | ep.Length<<40 | ep.Index<<24 | ep.Number
 }
@@ -0,0 +1,65 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEpochArithmetic(t *testing.T) {
+	assert.Equal(t, &EpochParams{Number: 11, Index: 1, Length: 2}, (&EpochParams{Number: 10, Index: 9, Length: 6}).Normalize())
+	assert.Equal(t, &EpochParams{Number: 10, Index: 0, Length: 1}, (&EpochParams{Number: 10}).Normalize())
+
+	a := &EpochParams{Number: 10, Index: 1, Length: 2}
+	b := &EpochParams{Number: 10, Index: 500, Length: 1000}
+	c := &EpochParams{Number: 10, Index: 2, Length: 3}
+	assert.Equal(t, 0, a.Cmp(b))
+	assert.Equal(t, -1, a.Cmp(c))
+	assert.Equal(t, 1, c.Cmp(a))
+	assert.Equal(t, 1, a.Cmp(&EpochParams{Number: 9, Index: 999, Length: 1000}))
+
+	assert.Equal(t, &EpochParams{Number: 21, Index: 1, Length: 6}, a.Add(c))
+	assert.Equal(t, &EpochParams{Number: 190, Index: 1, Length: 2}, a.Add(&EpochParams{Number: 180}))
+
+	diff, err := c.Sub(a)
+	assert.Nil(t, err)
+	assert.Equal(t, &EpochParams{Number: 0, Index: 1, Length: 6}, diff)
+	diff, err = (&EpochParams{Number: 12, Index: 1, Length: 4}).Sub(a)
+	assert.Nil(t, err)
+	assert.Equal(t, &EpochParams{Number: 1, Index: 3, Length: 4}, diff)
+	_, err = a.Sub(c)
+	assert.NotNil(t, err)
+}
+
+func TestNextEpochOfPeriod(t *testing.T) {
+	deposit := &EpochParams{Number: 10, Index: 100, Length: 1000}
+	assert.Equal(t, deposit, NextEpochOfPeriod(deposit, 180, deposit))
+	assert.Equal(t, &EpochParams{Number: 190, Index: 100, Length: 1000},
+		NextEpochOfPeriod(deposit, 180, &EpochParams{Number: 10, Index: 101, Length: 1000}))
+	assert.Equal(t, &EpochParams{Number: 190, Index: 100, Length: 1000},
+		NextEpochOfPeriod(deposit, 180, &EpochParams{Number: 190, Index: 50, Length: 500}))
+	assert.Equal(t, &EpochParams{Number: 370, Index: 100, Length: 1000},
+		NextEpochOfPeriod(deposit, 180, &EpochParams{Number: 190, Index: 51, Length: 500}))
+}
+
+func TestEarliestEpoch(t *testing.T) {
+	start := &EpochParams{Number: 5, Index: 1, Length: 4}
+	step := &EpochParams{Number: 0, Index: 1, Length: 2}
+
+	at := &EpochParams{Number: 100, Index: 0, Length: 1}
+	notBefore := func(epoch *EpochParams) bool {
+		return epoch.Cmp(at) >= 0
+	}
+	assert.Equal(t, &EpochParams{Number: 100, Index: 1, Length: 4}, EarliestEpoch(start, step, notBefore))
+	assert.Equal(t, start, EarliestEpoch(start, step, func(*EpochParams) bool { return true }))
+	assert.Nil(t, EarliestEpoch(start, &EpochParams{}, notBefore))
+	assert.Nil(t, EarliestEpoch(start, step, func(*EpochParams) bool { return false }))
+
+	// earliest epoch satisfying an absolute epoch since
+	since, err := NewAbsoluteEpochSince(&EpochParams{Number: 30, Index: 2, Length: 3})
+	assert.Nil(t, err)
+	epoch := EarliestEpoch(start, &EpochParams{Number: 1}, func(epoch *EpochParams) bool {
+		return epoch.Cmp(since.Epoch()) >= 0
+	})
+	assert.Equal(t, &EpochParams{Number: 31, Index: 1, Length: 4}, epoch)
+}
@@ -13,8 +13,10 @@ type TransactionPoint struct {
 }
 
 type LiveCell struct {
-	CellOutput *CellOutput       `json:"cell_output"`
-	CreatedBy  *TransactionPoint `json:"created_by"`
+	CellOutput    *CellOutput       `json:"cell_output"`
+	CreatedBy     *TransactionPoint `json:"created_by"`
+	Cellbase      bool              `json:"cellbase"`
+	OutputDataLen uint64            `json:"output_data_len"`
 }
 
 type CellTransaction struct {
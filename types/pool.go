@@ -8,3 +8,20 @@ type TxPoolInfo struct {
 	TotalTxCycles    uint64 `json:"total_tx_cycles"`
 	TotalTxSize      uint64 `json:"total_tx_size"`
 }
+
+type PoolTransactionEntry struct {
+	Transaction *Transaction `json:"transaction"`
+	Cycles      uint64       `json:"cycles"`
+	Size        uint64       `json:"size"`
+	Fee         uint64       `json:"fee"`
+}
+
+type PoolTransactionReject struct {
+	Type        string `json:"type"`
+	Description string `json:"description"`
+}
+
+type RejectedTransaction struct {
+	Entry  *PoolTransactionEntry
+	Reason *PoolTransactionReject
+}
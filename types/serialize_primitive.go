@@ -3,15 +3,47 @@ package types
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
+	"fmt"
 	"reflect"
 )
 
 const u32Size uint = 4
 
+var (
+	ErrHeaderIsBroken     = errors.New("header is broken")
+	ErrTotalSizeNotMatch  = errors.New("total size not match")
+	ErrOffsetsNotMatch    = errors.New("offsets not match")
+	ErrFieldCountNotMatch = errors.New("field count not match")
+	ErrUnknownItem        = errors.New("unknown item")
+)
+
 type Serializer interface {
 	Serialize() ([]byte, error)
 }
 
+type Deserializer interface {
+	Deserialize(data []byte) error
+}
+
+// VerificationError describes malformed molecule input, Err is one of the Err* values above.
+type VerificationError struct {
+	Name string
+	Err  error
+}
+
+func (e *VerificationError) Error() string {
+	return fmt.Sprintf("deserialize %s error: %v", e.Name, e.Err)
+}
+
+func (e *VerificationError) Unwrap() error {
+	return e.Err
+}
+
+func verificationError(name string, err error) error {
+	return &VerificationError{Name: name, Err: err}
+}
+
 func SerializeUint(n uint) []byte {
 	b := make([]byte, u32Size)
 	binary.LittleEndian.PutUint32(b, uint32(n))
@@ -142,6 +174,11 @@ func SerializeDynVec(items [][]byte) []byte {
 //    Serialize all offset of fields as 32 bit unsigned integer in little-endian.
 //    Serialize all fields in it in the order they are declared.
 func SerializeTable(fields [][]byte) []byte {
+	// Empty table, just return size's bytes
+	if len(fields) == 0 {
+		return SerializeUint(u32Size)
+	}
+
 	size := u32Size
 	offsets := make([]uint, len(fields))
 
@@ -187,3 +224,170 @@ func SerializeOptionBytes(o []byte) ([]byte, error) {
 
 	return SerializeBytes(o), nil
 }
+
+func DeserializeUint(data []byte) (uint, error) {
+	if uint(len(data)) != u32Size {
+		return 0, verificationError("uint32", ErrTotalSizeNotMatch)
+	}
+
+	return uint(binary.LittleEndian.Uint32(data)), nil
+}
+
+func DeserializeUint64(data []byte) (uint64, error) {
+	if len(data) != 8 {
+		return 0, verificationError("uint64", ErrTotalSizeNotMatch)
+	}
+
+	return binary.LittleEndian.Uint64(data), nil
+}
+
+// DeserializeStruct deserialize struct
+// Split data into fields by their fixed sizes, the sizes must sum up to the length of data.
+func DeserializeStruct(data []byte, sizes []uint) ([][]byte, error) {
+	var total uint
+	for _, size := range sizes {
+		total += size
+	}
+	if uint(len(data)) != total {
+		return nil, verificationError("struct", ErrTotalSizeNotMatch)
+	}
+
+	fields := make([][]byte, len(sizes))
+	var offset uint
+	for i, size := range sizes {
+		fields[i] = data[offset : offset+size]
+		offset += size
+	}
+
+	return fields, nil
+}
+
+// DeserializeBytes deserialize bytes
+// The returned slice is a copy, it does not share memory with data.
+func DeserializeBytes(data []byte) ([]byte, error) {
+	if uint(len(data)) < u32Size {
+		return nil, verificationError("bytes", ErrHeaderIsBroken)
+	}
+
+	if uint(len(data))-u32Size != uint(binary.LittleEndian.Uint32(data)) {
+		return nil, verificationError("bytes", ErrTotalSizeNotMatch)
+	}
+
+	b := make([]byte, uint(len(data))-u32Size)
+	copy(b, data[u32Size:])
+
+	return b, nil
+}
+
+// DeserializeFixVec deserialize fixvec vector
+// The item count is read from the header and every item must be exactly itemSize bytes.
+func DeserializeFixVec(data []byte, itemSize uint) ([][]byte, error) {
+	if uint(len(data)) < u32Size {
+		return nil, verificationError("fixvec", ErrHeaderIsBroken)
+	}
+
+	count := uint(binary.LittleEndian.Uint32(data))
+	// Compare with division so a huge count can not overflow
+	if itemSize == 0 || (uint(len(data))-u32Size)%itemSize != 0 || (uint(len(data))-u32Size)/itemSize != count {
+		return nil, verificationError("fixvec", ErrTotalSizeNotMatch)
+	}
+
+	items := make([][]byte, count)
+	for i := uint(0); i < count; i++ {
+		start := u32Size + i*itemSize
+		items[i] = data[start : start+itemSize]
+	}
+
+	return items, nil
+}
+
+// DeserializeDynVec deserialize dynvec
+func DeserializeDynVec(data []byte) ([][]byte, error) {
+	items, err := deserializeOffsets(data)
+	if err != nil {
+		return nil, verificationError("dynvec", err)
+	}
+
+	return items, nil
+}
+
+// DeserializeTable deserialize table
+// The table must have exactly fieldCount fields, extra fields are rejected.
+func DeserializeTable(data []byte, fieldCount uint) ([][]byte, error) {
+	fields, err := deserializeOffsets(data)
+	if err != nil {
+		return nil, verificationError("table", err)
+	}
+	if uint(len(fields)) != fieldCount {
+		return nil, verificationError("table", ErrFieldCountNotMatch)
+	}
+
+	return fields, nil
+}
+
+// DeserializeOption deserialize option
+// Return false when the option is None, otherwise deserialize data into o.
+func DeserializeOption(data []byte, o Deserializer) (bool, error) {
+	if len(data) == 0 {
+		return false, nil
+	}
+
+	return true, o.Deserialize(data)
+}
+
+// DeserializeOptionBytes deserialize option bytes, None is returned as nil
+func DeserializeOptionBytes(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	return DeserializeBytes(data)
+}
+
+// deserializeOffsets split a table or dynvec into items, checking the header layout:
+//    The full size must equal the length of data.
+//    The first offset must point right after the offsets header.
+//    Offsets must be ascending and inside data.
+func deserializeOffsets(data []byte) ([][]byte, error) {
+	size := uint(len(data))
+	if size < u32Size {
+		return nil, ErrHeaderIsBroken
+	}
+
+	if uint(binary.LittleEndian.Uint32(data)) != size {
+		return nil, ErrTotalSizeNotMatch
+	}
+
+	if size == u32Size {
+		return [][]byte{}, nil
+	}
+
+	if size < u32Size*2 {
+		return nil, ErrHeaderIsBroken
+	}
+
+	first := uint(binary.LittleEndian.Uint32(data[u32Size:]))
+	if first%u32Size != 0 || first < u32Size*2 {
+		return nil, ErrOffsetsNotMatch
+	}
+	if size < first {
+		return nil, ErrHeaderIsBroken
+	}
+
+	count := first/u32Size - 1
+	offsets := make([]uint, count+1)
+	for i := uint(0); i < count; i++ {
+		offsets[i] = uint(binary.LittleEndian.Uint32(data[u32Size*(i+1):]))
+	}
+	offsets[count] = size
+
+	items := make([][]byte, count)
+	for i := uint(0); i < count; i++ {
+		if offsets[i] > offsets[i+1] {
+			return nil, ErrOffsetsNotMatch
+		}
+		items[i] = data[offsets[i]:offsets[i+1]]
+	}
+
+	return items, nil
+}
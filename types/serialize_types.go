@@ -3,6 +3,19 @@ package types
 import (
 	"bytes"
 	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/ququzone/ckb-sdk-go/crypto/blake2b"
+)
+
+const (
+	outPointSize        uint = HashLength + 4
+	cellInputSize       uint = 8 + outPointSize
+	cellDepSize         uint = outPointSize + 1
+	nonceSize           uint = 16
+	proposalShortIdSize uint = 10
 )
 
 func (h Hash) Serialize() ([]byte, error) {
@@ -14,6 +27,8 @@ func (t ScriptHashType) Serialize() ([]byte, error) {
 		return []byte{00}, nil
 	} else if t == HashTypeType {
 		return []byte{01}, nil
+	} else if t == HashTypeData1 {
+		return []byte{02}, nil
 	}
 	return nil, errors.New("invalid script hash type")
 }
@@ -166,6 +181,141 @@ func (t *Transaction) Serialize() ([]byte, error) {
 	return SerializeTable(fields), nil
 }
 
+// SerializeWithWitnesses serialize transaction together with witnesses
+func (t *Transaction) SerializeWithWitnesses() ([]byte, error) {
+	raw, err := t.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	wts := make([][]byte, len(t.Witnesses))
+	for i := 0; i < len(t.Witnesses); i++ {
+		wts[i] = SerializeBytes(t.Witnesses[i])
+	}
+	wtsBytes := SerializeDynVec(wts)
+
+	return SerializeTable([][]byte{raw, wtsBytes}), nil
+}
+
+// SerializeRaw serialize raw header, the header without nonce
+func (h *Header) SerializeRaw() ([]byte, error) {
+	fields := [][]byte{
+		SerializeUint(h.Version),
+		SerializeUint(h.CompactTarget),
+		SerializeUint64(h.Timestamp),
+		SerializeUint64(h.Number),
+		SerializeUint64(h.Epoch),
+		h.ParentHash.Bytes(),
+		h.TransactionsRoot.Bytes(),
+		h.ProposalsHash.Bytes(),
+		h.UnclesHash.Bytes(),
+		h.Dao.Bytes(),
+	}
+
+	return SerializeStruct(fields), nil
+}
+
+// Serialize header
+func (h *Header) Serialize() ([]byte, error) {
+	raw, err := h.SerializeRaw()
+	if err != nil {
+		return nil, err
+	}
+
+	// Nonce is an uint128 in little-endian
+	nonce := make([]byte, nonceSize)
+	if h.Nonce != nil {
+		if h.Nonce.Sign() < 0 || uint(h.Nonce.BitLen()) > nonceSize*8 {
+			return nil, errors.New("invalid header nonce")
+		}
+		b := h.Nonce.Bytes()
+		for i := 0; i < len(b); i++ {
+			nonce[i] = b[len(b)-1-i]
+		}
+	}
+
+	return SerializeStruct([][]byte{raw, nonce}), nil
+}
+
+// Serialize uncle block
+func (u *UncleBlock) Serialize() ([]byte, error) {
+	h, err := u.Header.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	ps, err := serializeProposals(u.Proposals)
+	if err != nil {
+		return nil, err
+	}
+
+	return SerializeTable([][]byte{h, ps}), nil
+}
+
+// Serialize block
+func (b *Block) Serialize() ([]byte, error) {
+	h, err := b.Header.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	ucs := make([][]byte, len(b.Uncles))
+	for i := 0; i < len(b.Uncles); i++ {
+		uc, err := b.Uncles[i].Serialize()
+		if err != nil {
+			return nil, err
+		}
+
+		ucs[i] = uc
+	}
+	ucsBytes := SerializeDynVec(ucs)
+
+	txs := make([][]byte, len(b.Transactions))
+	for i := 0; i < len(b.Transactions); i++ {
+		tx, err := b.Transactions[i].SerializeWithWitnesses()
+		if err != nil {
+			return nil, err
+		}
+
+		txs[i] = tx
+	}
+	txsBytes := SerializeDynVec(txs)
+
+	ps, err := serializeProposals(b.Proposals)
+	if err != nil {
+		return nil, err
+	}
+
+	return SerializeTable([][]byte{h, ucsBytes, txsBytes, ps}), nil
+}
+
+// serializeProposals serialize hex encoded proposal short ids as fixvec
+func serializeProposals(proposals []string) ([]byte, error) {
+	ids, err := decodeProposals(proposals)
+	if err != nil {
+		return nil, err
+	}
+
+	return SerializeFixVec(ids), nil
+}
+
+func decodeProposals(proposals []string) ([][]byte, error) {
+	ids := make([][]byte, len(proposals))
+	for i := 0; i < len(proposals); i++ {
+		id, err := hexutil.Decode(proposals[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid proposal %s: %v", proposals[i], err)
+		}
+		if uint(len(id)) != proposalShortIdSize {
+			return nil, fmt.Errorf("invalid proposal %s: length must be %d", proposals[i], proposalShortIdSize)
+		}
+
+		ids[i] = id
+	}
+
+	return ids, nil
+}
+
 func (w *WitnessArgs) Serialize() ([]byte, error) {
 	l, err := SerializeOptionBytes(w.Lock)
 	if err != nil {
@@ -184,3 +334,264 @@ func (w *WitnessArgs) Serialize() ([]byte, error) {
 
 	return SerializeTable([][]byte{l, i, o}), nil
 }
+
+func (h *Hash) Deserialize(data []byte) error {
+	if len(data) != HashLength {
+		return verificationError("hash", ErrTotalSizeNotMatch)
+	}
+	copy(h[:], data)
+
+	return nil
+}
+
+func (t *ScriptHashType) Deserialize(data []byte) error {
+	if len(data) != 1 {
+		return verificationError("script hash type", ErrTotalSizeNotMatch)
+	}
+	if data[0] == 00 {
+		*t = HashTypeData
+	} else if data[0] == 01 {
+		*t = HashTypeType
+	} else if data[0] == 02 {
+		*t = HashTypeData1
+	} else {
+		return verificationError("script hash type", ErrUnknownItem)
+	}
+	return nil
+}
+
+// Deserialize dep type
+func (t *DepType) Deserialize(data []byte) error {
+	if len(data) != 1 {
+		return verificationError("dep type", ErrTotalSizeNotMatch)
+	}
+	if data[0] == 00 {
+		*t = DepTypeCode
+	} else if data[0] == 01 {
+		*t = DepTypeDepGroup
+	} else {
+		return verificationError("dep type", ErrUnknownItem)
+	}
+	return nil
+}
+
+// Deserialize script
+func (script *Script) Deserialize(data []byte) error {
+	fields, err := DeserializeTable(data, 3)
+	if err != nil {
+		return err
+	}
+
+	if err := script.CodeHash.Deserialize(fields[0]); err != nil {
+		return err
+	}
+
+	if err := script.HashType.Deserialize(fields[1]); err != nil {
+		return err
+	}
+
+	script.Args, err = DeserializeBytes(fields[2])
+	return err
+}
+
+// Deserialize outpoint
+func (o *OutPoint) Deserialize(data []byte) error {
+	fields, err := DeserializeStruct(data, []uint{HashLength, u32Size})
+	if err != nil {
+		return err
+	}
+
+	if err := o.TxHash.Deserialize(fields[0]); err != nil {
+		return err
+	}
+
+	o.Index, err = DeserializeUint(fields[1])
+	return err
+}
+
+// Deserialize cell input
+func (i *CellInput) Deserialize(data []byte) error {
+	fields, err := DeserializeStruct(data, []uint{8, outPointSize})
+	if err != nil {
+		return err
+	}
+
+	i.Since, err = DeserializeUint64(fields[0])
+	if err != nil {
+		return err
+	}
+
+	i.PreviousOutput = &OutPoint{}
+	return i.PreviousOutput.Deserialize(fields[1])
+}
+
+// Deserialize cell output
+func (o *CellOutput) Deserialize(data []byte) error {
+	fields, err := DeserializeTable(data, 3)
+	if err != nil {
+		return err
+	}
+
+	o.Capacity, err = DeserializeUint64(fields[0])
+	if err != nil {
+		return err
+	}
+
+	o.Lock = &Script{}
+	if err := o.Lock.Deserialize(fields[1]); err != nil {
+		return err
+	}
+
+	o.Type = &Script{}
+	some, err := DeserializeOption(fields[2], o.Type)
+	if err != nil {
+		return err
+	}
+	if !some {
+		o.Type = nil
+	}
+
+	return nil
+}
+
+// Deserialize cell dep
+func (d *CellDep) Deserialize(data []byte) error {
+	fields, err := DeserializeStruct(data, []uint{outPointSize, 1})
+	if err != nil {
+		return err
+	}
+
+	d.OutPoint = &OutPoint{}
+	if err := d.OutPoint.Deserialize(fields[0]); err != nil {
+		return err
+	}
+
+	return d.DepType.Deserialize(fields[1])
+}
+
+// Deserialize transaction, the counterpart of Serialize which only contains the raw transaction.
+// Witnesses are left untouched and Hash is computed from data.
+func (t *Transaction) Deserialize(data []byte) error {
+	fields, err := DeserializeTable(data, 6)
+	if err != nil {
+		return err
+	}
+
+	t.Version, err = DeserializeUint(fields[0])
+	if err != nil {
+		return err
+	}
+
+	cds, err := DeserializeFixVec(fields[1], cellDepSize)
+	if err != nil {
+		return err
+	}
+	t.CellDeps = make([]*CellDep, len(cds))
+	for i := 0; i < len(cds); i++ {
+		t.CellDeps[i] = &CellDep{}
+		if err := t.CellDeps[i].Deserialize(cds[i]); err != nil {
+			return err
+		}
+	}
+
+	hds, err := DeserializeFixVec(fields[2], HashLength)
+	if err != nil {
+		return err
+	}
+	t.HeaderDeps = make([]Hash, len(hds))
+	for i := 0; i < len(hds); i++ {
+		if err := t.HeaderDeps[i].Deserialize(hds[i]); err != nil {
+			return err
+		}
+	}
+
+	ips, err := DeserializeFixVec(fields[3], cellInputSize)
+	if err != nil {
+		return err
+	}
+	t.Inputs = make([]*CellInput, len(ips))
+	for i := 0; i < len(ips); i++ {
+		t.Inputs[i] = &CellInput{}
+		if err := t.Inputs[i].Deserialize(ips[i]); err != nil {
+			return err
+		}
+	}
+
+	ops, err := DeserializeDynVec(fields[4])
+	if err != nil {
+		return err
+	}
+	t.Outputs = make([]*CellOutput, len(ops))
+	for i := 0; i < len(ops); i++ {
+		t.Outputs[i] = &CellOutput{}
+		if err := t.Outputs[i].Deserialize(ops[i]); err != nil {
+			return err
+		}
+	}
+
+	ods, err := DeserializeDynVec(fields[5])
+	if err != nil {
+		return err
+	}
+	t.OutputsData = make([][]byte, len(ods))
+	for i := 0; i < len(ods); i++ {
+		t.OutputsData[i], err = DeserializeBytes(ods[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	hash, err := blake2b.Blake256(data)
+	if err != nil {
+		return err
+	}
+	t.Hash = BytesToHash(hash)
+
+	return nil
+}
+
+// DeserializeWithWitnesses deserialize transaction together with witnesses, the counterpart of SerializeWithWitnesses
+func (t *Transaction) DeserializeWithWitnesses(data []byte) error {
+	fields, err := DeserializeTable(data, 2)
+	if err != nil {
+		return err
+	}
+
+	if err := t.Deserialize(fields[0]); err != nil {
+		return err
+	}
+
+	wts, err := DeserializeDynVec(fields[1])
+	if err != nil {
+		return err
+	}
+	t.Witnesses = make([][]byte, len(wts))
+	for i := 0; i < len(wts); i++ {
+		t.Witnesses[i], err = DeserializeBytes(wts[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w *WitnessArgs) Deserialize(data []byte) error {
+	fields, err := DeserializeTable(data, 3)
+	if err != nil {
+		return err
+	}
+
+	w.Lock, err = DeserializeOptionBytes(fields[0])
+	if err != nil {
+		return err
+	}
+
+	w.InputType, err = DeserializeOptionBytes(fields[1])
+	if err != nil {
+		return err
+	}
+
+	w.OutputType, err = DeserializeOptionBytes(fields[2])
+	return err
+}
@@ -0,0 +1,155 @@
+package types
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func testTransaction() *Transaction {
+	return &Transaction{
+		Version:    0,
+		HeaderDeps: []Hash{HexToHash("0x386bafd53bade6bf769c9b10f545e31ea744cb6ebc5f1c8178f307e8dce157a6")},
+		CellDeps: []*CellDep{
+			{
+				OutPoint: &OutPoint{
+					TxHash: HexToHash("0xace5ea83c478bb866edf122ff862085789158f5cbff155b7bb5f13058555b708"),
+					Index:  0,
+				},
+				DepType: DepTypeDepGroup,
+			},
+		},
+		Inputs: []*CellInput{
+			{
+				Since: 0x2000000000000000,
+				PreviousOutput: &OutPoint{
+					TxHash: HexToHash("0xc8cfe3d09b0a50fd2df3bd79dbadca23b7eb1f58087942d7266abea93459fce1"),
+					Index:  1,
+				},
+			},
+		},
+		Outputs: []*CellOutput{
+			{
+				Capacity: 400000000000,
+				Lock: &Script{
+					CodeHash: HexToHash("0x9bd7e06f3ecf4be0f2fcd2188b23f1b9fcc88e5d4b65a8637b17723bbda3cce8"),
+					HashType: HashTypeType,
+					Args:     common.FromHex("0xedcda9513fa030ce4308e29245a22c022d0443bb"),
+				},
+				Type: &Script{
+					CodeHash: HexToHash("0x82d76d1b75fe2fd9a27dfbaa65a039221a380d76c926f378d3f81cf3e7e13f2e"),
+					HashType: HashTypeType,
+					Args:     []byte{},
+				},
+			},
+			{
+				Capacity: 99999997000,
+				Lock: &Script{
+					CodeHash: HexToHash("0x9bd7e06f3ecf4be0f2fcd2188b23f1b9fcc88e5d4b65a8637b17723bbda3cce8"),
+					HashType: HashTypeType,
+					Args:     common.FromHex("0xedcda9513fa030ce4308e29245a22c022d0443bb"),
+				},
+			},
+		},
+		OutputsData: [][]byte{make([]byte, 8), {}},
+		Witnesses:   [][]byte{make([]byte, 85), {}},
+	}
+}
+
+func TestWitnessArgsSerialize(t *testing.T) {
+	w := &WitnessArgs{Lock: make([]byte, 65)}
+
+	data, err := w.Serialize()
+	assert.Nil(t, err)
+	assert.Equal(t, "0x5500000010000000550000005500000041000000"+common.Bytes2Hex(make([]byte, 65)), common.ToHex(data))
+
+	var decoded WitnessArgs
+	assert.Nil(t, decoded.Deserialize(data))
+	assert.Equal(t, w.Lock, decoded.Lock)
+	assert.Nil(t, decoded.InputType)
+	assert.Nil(t, decoded.OutputType)
+}
+
+func TestTransactionDeserialize(t *testing.T) {
+	tx := testTransaction()
+
+	data, err := tx.Serialize()
+	assert.Nil(t, err)
+	hash, err := tx.ComputeHash()
+	assert.Nil(t, err)
+
+	var decoded Transaction
+	assert.Nil(t, decoded.Deserialize(data))
+	assert.Equal(t, hash, decoded.Hash)
+	assert.Nil(t, decoded.Witnesses)
+
+	decoded.Hash = Hash{}
+	decoded.Witnesses = tx.Witnesses
+	assert.Equal(t, tx, &decoded)
+}
+
+func TestTransactionDeserializeWithWitnesses(t *testing.T) {
+	tx := testTransaction()
+
+	data, err := tx.SerializeWithWitnesses()
+	assert.Nil(t, err)
+
+	var decoded Transaction
+	assert.Nil(t, decoded.DeserializeWithWitnesses(data))
+	assert.Equal(t, tx.Witnesses, decoded.Witnesses)
+	assert.Equal(t, tx.Outputs, decoded.Outputs)
+
+	again, err := decoded.SerializeWithWitnesses()
+	assert.Nil(t, err)
+	assert.Equal(t, data, again)
+}
+
+func TestDeserializeMalformed(t *testing.T) {
+	script := testTransaction().Outputs[0].Lock
+	data, err := script.Serialize()
+	assert.Nil(t, err)
+
+	var s Script
+
+	err = s.Deserialize(data[:3])
+	assert.True(t, errors.Is(err, ErrHeaderIsBroken))
+
+	err = s.Deserialize(data[:len(data)-1])
+	assert.True(t, errors.Is(err, ErrTotalSizeNotMatch))
+
+	broken := append([]byte{}, data...)
+	broken[4] = 0x0d
+	err = s.Deserialize(broken)
+	assert.True(t, errors.Is(err, ErrOffsetsNotMatch))
+
+	broken = append([]byte{}, data...)
+	broken[8], broken[12] = broken[12], broken[8]
+	err = s.Deserialize(broken)
+	assert.True(t, errors.Is(err, ErrOffsetsNotMatch))
+
+	broken = append([]byte{}, data...)
+	broken[4+4*3+HashLength] = 0x03
+	err = s.Deserialize(broken)
+	assert.True(t, errors.Is(err, ErrUnknownItem))
+
+	err = s.Deserialize(SerializeTable([][]byte{{0x01}, {0x02}}))
+	assert.True(t, errors.Is(err, ErrFieldCountNotMatch))
+
+	_, err = DeserializeFixVec([]byte{0xff, 0xff, 0xff, 0xff, 0x00}, 1)
+	assert.True(t, errors.Is(err, ErrTotalSizeNotMatch))
+
+	var e *VerificationError
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, "fixvec", e.Name)
+}
+
+func TestSerializeEmptyTable(t *testing.T) {
+	data := SerializeTable([][]byte{})
+	assert.Equal(t, []byte{0x04, 0x00, 0x00, 0x00}, data)
+
+	fields, err := DeserializeTable(data, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(fields))
+}
@@ -0,0 +1,169 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+)
+
+// SinceMetric is the unit of since value
+type SinceMetric uint8
+
+const (
+	SinceMetricBlockNumber SinceMetric = 0
+	SinceMetricEpoch       SinceMetric = 1
+	// SinceMetricTimestamp is median time of the previous 37 blocks in seconds
+	SinceMetricTimestamp SinceMetric = 2
+)
+
+const (
+	sinceRelativeFlag uint64 = 1 << 63
+	sinceMetricShift         = 61
+	sinceMetricMask   uint64 = 0x6000000000000000
+	sinceRemainMask   uint64 = 0x1f00000000000000
+	sinceValueMask    uint64 = 0x00ffffffffffffff
+)
+
+var ErrInvalidSince = errors.New("invalid since")
+
+// Since is the lock time of a transaction input, see RFC 0017.
+// The highest bit is relative flag, the next 2 bits are metric, the lowest 56 bits are value.
+type Since uint64
+
+func NewAbsoluteBlockNumberSince(number uint64) (Since, error) {
+	return newSince(false, SinceMetricBlockNumber, number)
+}
+
+func NewRelativeBlockNumberSince(number uint64) (Since, error) {
+	return newSince(true, SinceMetricBlockNumber, number)
+}
+
+func NewAbsoluteEpochSince(epoch *EpochParams) (Since, error) {
+	value, err := epochSinceValue(epoch)
+	if err != nil {
+		return 0, err
+	}
+	return newSince(false, SinceMetricEpoch, value)
+}
+
+func NewRelativeEpochSince(epoch *EpochParams) (Since, error) {
+	value, err := epochSinceValue(epoch)
+	if err != nil {
+		return 0, err
+	}
+	return newSince(true, SinceMetricEpoch, value)
+}
+
+// NewAbsoluteTimestampSince returns since of median time in seconds
+func NewAbsoluteTimestampSince(seconds uint64) (Since, error) {
+	return newSince(false, SinceMetricTimestamp, seconds)
+}
+
+// NewRelativeTimestampSince returns since of seconds after the median time of the block committing the input cell
+func NewRelativeTimestampSince(seconds uint64) (Since, error) {
+	return newSince(true, SinceMetricTimestamp, seconds)
+}
+
+func newSince(relative bool, metric SinceMetric, value uint64) (Since, error) {
+	if value&^sinceValueMask != 0 {
+		return 0, fmt.Errorf("%w: value %d exceeds 56 bits", ErrInvalidSince, value)
+	}
+	since := uint64(metric)<<sinceMetricShift | value
+	if relative {
+		since |= sinceRelativeFlag
+	}
+	return Since(since), nil
+}
+
+func epochSinceValue(epoch *EpochParams) (uint64, error) {
+	if epoch.Number > MaxEpochNumber || epoch.Index > 0xffff || epoch.Length > 0xffff {
+		return 0, fmt.Errorf("%w: epoch %d %d/%d out of range", ErrInvalidSince, epoch.Number, epoch.Index, epoch.Length)
+	}
+	if !epochWellFormed(epoch) {
+		return 0, fmt.Errorf("%w: epoch fraction %d/%d", ErrInvalidSince, epoch.Index, epoch.Length)
+	}
+	return epoch.Length<<40 | epoch.Index<<24 | epoch.Number, nil
+}
+
+// epochWellFormed reports whether index is a proper fraction of length, 0/0 is a whole epoch
+func epochWellFormed(epoch *EpochParams) bool {
+	if epoch.Length == 0 {
+		return epoch.Index == 0
+	}
+	return epoch.Index < epoch.Length
+}
+
+// ParseSince validates flags of since as the node does, 0 means no lock
+func ParseSince(since uint64) (Since, error) {
+	if since&sinceRemainMask != 0 {
+		return 0, fmt.Errorf("%w: reserved bits of %#x are set", ErrInvalidSince, since)
+	}
+	if since&sinceMetricMask == sinceMetricMask {
+		return 0, fmt.Errorf("%w: unknown metric of %#x", ErrInvalidSince, since)
+	}
+	s := Since(since)
+	if s.Metric() == SinceMetricEpoch && !epochWellFormed(ParseEpoch(s.Value())) {
+		return 0, fmt.Errorf("%w: epoch fraction of %#x", ErrInvalidSince, since)
+	}
+	return s, nil
+}
+
+func (s Since) IsRelative() bool {
+	return uint64(s)&sinceRelativeFlag != 0
+}
+
+func (s Since) Metric() SinceMetric {
+	return SinceMetric(uint64(s) & sinceMetricMask >> sinceMetricShift)
+}
+
+// Value is block number, epoch or seconds by metric
+func (s Since) Value() uint64 {
+	return uint64(s) & sinceValueMask
+}
+
+// Epoch returns the epoch value, nil when metric is not epoch
+func (s Since) Epoch() *EpochParams {
+	if s.Metric() != SinceMetricEpoch {
+		return nil
+	}
+	return ParseEpoch(s.Value())
+}
+
+func (s Since) Uint64() uint64 {
+	return uint64(s)
+}
+
+// Satisfied reports whether an input with since can be committed in the block of header.
+// start is the header of the block committing the input cell, it is required by relative since only.
+// Timestamp since is compared with header timestamp, it is an approximation as the node uses median time
+// of the previous 37 blocks which is earlier.
+func (s Since) Satisfied(header *Header, start *Header) (bool, error) {
+	if s == 0 {
+		return true, nil
+	}
+	if s.IsRelative() && start == nil {
+		return false, errors.New("start header is required by relative since")
+	}
+
+	switch s.Metric() {
+	case SinceMetricBlockNumber:
+		target := s.Value()
+		if s.IsRelative() {
+			target += start.Number
+		}
+		return header.Number >= target, nil
+	case SinceMetricEpoch:
+		target := s.Epoch()
+		if s.IsRelative() {
+			target = target.Add(ParseEpoch(start.Epoch))
+		}
+		return ParseEpoch(header.Epoch).Cmp(target) >= 0, nil
+	case SinceMetricTimestamp:
+		target := s.Value()
+		if s.IsRelative() {
+			target += start.Timestamp / 1000
+		}
+		return header.Timestamp/1000 >= target, nil
+	default:
+		return false, fmt.Errorf("%w: unknown metric of %#x", ErrInvalidSince, uint64(s))
+	}
+}
@@ -0,0 +1,87 @@
+package types
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSince(t *testing.T) {
+	for _, since := range []uint64{0, 0x8000000000000064, 0x4000000000000064, 0x20000a0005000064, 0x2000000000000064} {
+		_, err := ParseSince(since)
+		assert.Nil(t, err)
+	}
+	for _, since := range []uint64{0x20000a000a000064, 0x2000000005000064, 0x0100000000000000, 0xe000000000000000} {
+		_, err := ParseSince(since)
+		assert.True(t, errors.Is(err, ErrInvalidSince))
+	}
+
+	since, err := ParseSince(0xa0000a0005000064)
+	assert.Nil(t, err)
+	assert.True(t, since.IsRelative())
+	assert.Equal(t, SinceMetricEpoch, since.Metric())
+	assert.Equal(t, &EpochParams{Length: 10, Index: 5, Number: 100}, since.Epoch())
+}
+
+func TestNewSince(t *testing.T) {
+	since, err := NewAbsoluteBlockNumberSince(100)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0x0000000000000064), since.Uint64())
+
+	since, err = NewRelativeBlockNumberSince(100)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0x8000000000000064), since.Uint64())
+
+	since, err = NewAbsoluteTimestampSince(1600000000)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0x400000005f5e1000), since.Uint64())
+	assert.Nil(t, since.Epoch())
+
+	since, err = NewRelativeEpochSince(&EpochParams{Length: 10, Index: 5, Number: 100})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0xa0000a0005000064), since.Uint64())
+
+	epoch := &EpochParams{Length: 1800, Index: 10, Number: 370}
+	since, err = NewAbsoluteEpochSince(epoch)
+	assert.Nil(t, err)
+	assert.Equal(t, epoch.Uint64(), since.Uint64())
+
+	_, err = NewAbsoluteEpochSince(&EpochParams{Length: 10, Index: 10, Number: 1})
+	assert.True(t, errors.Is(err, ErrInvalidSince))
+	_, err = NewAbsoluteBlockNumberSince(1 << 56)
+	assert.True(t, errors.Is(err, ErrInvalidSince))
+}
+
+func TestSinceSatisfied(t *testing.T) {
+	start := &Header{Number: 1000, Epoch: 10<<40 | 5<<24 | 10, Timestamp: 1600000000000}
+	header := &Header{Number: 1100, Epoch: 10<<40 | 2<<24 | 12, Timestamp: 1600000100000}
+
+	cases := []struct {
+		since     func() (Since, error)
+		satisfied bool
+	}{
+		{func() (Since, error) { return NewAbsoluteBlockNumberSince(1100) }, true},
+		{func() (Since, error) { return NewAbsoluteBlockNumberSince(1101) }, false},
+		{func() (Since, error) { return NewRelativeBlockNumberSince(100) }, true},
+		{func() (Since, error) { return NewRelativeBlockNumberSince(101) }, false},
+		{func() (Since, error) { return NewAbsoluteEpochSince(&EpochParams{Length: 5, Index: 1, Number: 12}) }, true},
+		{func() (Since, error) { return NewAbsoluteEpochSince(&EpochParams{Length: 4, Index: 1, Number: 12}) }, false},
+		// 10 + 1/2 + 1 + 7/10 = 12 + 2/10
+		{func() (Since, error) { return NewRelativeEpochSince(&EpochParams{Length: 10, Index: 7, Number: 1}) }, true},
+		{func() (Since, error) { return NewRelativeEpochSince(&EpochParams{Length: 10, Index: 8, Number: 1}) }, false},
+		{func() (Since, error) { return NewAbsoluteTimestampSince(1600000100) }, true},
+		{func() (Since, error) { return NewRelativeTimestampSince(101) }, false},
+	}
+	for i, c := range cases {
+		since, err := c.since()
+		assert.Nil(t, err)
+		satisfied, err := since.Satisfied(header, start)
+		assert.Nil(t, err)
+		assert.Equal(t, c.satisfied, satisfied, "case %d", i)
+	}
+
+	since, _ := NewRelativeBlockNumberSince(1)
+	_, err := since.Satisfied(header, nil)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,68 @@
+package udt
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ququzone/ckb-sdk-go/crypto"
+	"github.com/ququzone/ckb-sdk-go/transaction"
+	"github.com/ququzone/ckb-sdk-go/types"
+	"github.com/ququzone/ckb-sdk-go/utils"
+)
+
+// Issue mints UDT of owner lock, the transaction must have an input locked by owner which is ensured
+// by collecting capacity from owner cells.
+type Issue struct {
+	Transaction *types.Transaction
+	Owner       *types.Script
+	Type        *types.Script
+	group       []int
+	witnessArgs *types.WitnessArgs
+}
+
+func NewIssue(scripts *utils.SystemScripts, owner *types.Script) (*Issue, error) {
+	udtType, err := NewTypeScript(scripts, owner)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := NewSUDTTx(scripts)
+	if err != nil {
+		return nil, err
+	}
+	return &Issue{
+		Transaction: tx,
+		Owner:       owner,
+		Type:        udtType,
+	}, nil
+}
+
+// AddOutput mints amount to lock
+func (i *Issue) AddOutput(lock *types.Script, amount *big.Int) error {
+	return AddOutput(i.Transaction, lock, i.Type, amount)
+}
+
+// Build collects owner cells for output capacity and fee, collector must collect cells of owner lock
+func (i *Issue) Build(collector *utils.CellCollector, feeRate uint64) (*types.Transaction, error) {
+	if !i.Owner.Equals(collector.LockScript) {
+		return nil, errors.New("collector lock is not owner")
+	}
+	group, witnessArgs, err := transaction.NewBuilder(i.Transaction, collector, i.Owner, feeRate).Build()
+	if err != nil {
+		return nil, err
+	}
+	i.group = group
+	i.witnessArgs = witnessArgs
+	return i.Transaction, nil
+}
+
+func (i *Issue) Sign(key crypto.Key) (*types.Transaction, error) {
+	if len(i.group) == 0 {
+		return nil, errors.New("must build transaction first")
+	}
+	err := transaction.SingleSignTransaction(i.Transaction, i.group, i.witnessArgs, key)
+	if err != nil {
+		return nil, fmt.Errorf("sign transaction error: %v", err)
+	}
+	return i.Transaction, nil
+}
@@ -0,0 +1,115 @@
+package udt
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ququzone/ckb-sdk-go/crypto"
+	"github.com/ququzone/ckb-sdk-go/transaction"
+	"github.com/ququzone/ckb-sdk-go/types"
+	"github.com/ququzone/ckb-sdk-go/utils"
+)
+
+// Transfer sends UDT from a secp256k1 lock, the remaining UDT goes back to sender in a change cell and
+// capacity of outputs and fee is paid by plain cells of sender.
+type Transfer struct {
+	Transaction *types.Transaction
+	From        *types.Script
+	Type        *types.Script
+	amount      *big.Int
+	group       []int
+	witnessArgs *types.WitnessArgs
+}
+
+func NewTransfer(scripts *utils.SystemScripts, udtType *types.Script, from *types.Script) (*Transfer, error) {
+	tx, err := NewSUDTTx(scripts)
+	if err != nil {
+		return nil, err
+	}
+	return &Transfer{
+		Transaction: tx,
+		From:        from,
+		Type:        udtType,
+		amount:      big.NewInt(0),
+	}, nil
+}
+
+// AddOutput sends amount to lock, the sum of amounts is collected from sender cells by Build
+func (t *Transfer) AddOutput(lock *types.Script, amount *big.Int) error {
+	if err := AddOutput(t.Transaction, lock, t.Type, amount); err != nil {
+		return err
+	}
+	t.amount = new(big.Int).Add(t.amount, amount)
+	return nil
+}
+
+// Build collects UDT cells and plain cells of sender by collector, collector lock must be sender lock
+func (t *Transfer) Build(collector *utils.CellCollector, feeRate uint64) (*types.Transaction, error) {
+	if !t.From.Equals(collector.LockScript) {
+		return nil, errors.New("collector lock is not sender")
+	}
+	if len(t.Transaction.Inputs) > 0 {
+		return nil, errors.New("transaction already built")
+	}
+
+	tx, group, witnessArgs, err := CompleteTransfer(t.Transaction, collector, t.Type, t.amount, 0, feeRate)
+	if err != nil {
+		return nil, err
+	}
+
+	*t.Transaction = *tx
+	t.group = group
+	t.witnessArgs = witnessArgs
+	return t.Transaction, nil
+}
+
+func (t *Transfer) Sign(key crypto.Key) (*types.Transaction, error) {
+	if len(t.group) == 0 {
+		return nil, errors.New("must build transaction first")
+	}
+	err := transaction.SingleSignTransaction(t.Transaction, t.group, t.witnessArgs, key)
+	if err != nil {
+		return nil, fmt.Errorf("sign transaction error: %v", err)
+	}
+	return t.Transaction, nil
+}
+
+// CompleteTransfer returns a copy of tx with inputs collected by collector: UDT cells of udtType for amount and
+// plain cells for capacity and fee, UDT change and capacity change go back to the collector lock.
+// inputCapacity is the capacity of inputs already in tx.
+// Returns group and witness args of collected inputs for signing, they are all in the collector lock group.
+func CompleteTransfer(tx *types.Transaction, collector *utils.CellCollector, udtType *types.Script, amount *big.Int, inputCapacity uint64, feeRate uint64) (*types.Transaction, []int, *types.WitnessArgs, error) {
+	result, err := collectAmount(collector, udtType, amount)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	total := resultAmount(result)
+	if total.Cmp(amount) < 0 {
+		return nil, nil, nil, fmt.Errorf("%w: %s is less than %s", ErrInsufficientBalance, total.String(), amount.String())
+	}
+
+	tx = tx.Clone()
+	if change := new(big.Int).Sub(total, amount); change.Sign() > 0 {
+		if err := AddOutput(tx, collector.LockScript, udtType, change); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	group, witnessArgs, err := transaction.AddInputsForTransaction(tx, result.Cells)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	builder := transaction.NewBuilder(tx, collector, collector.LockScript, feeRate)
+	builder.InputCapacity = inputCapacity + result.Capacity
+	capacityGroup, _, err := builder.Build()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	// all collected inputs are in the same lock group, only the first witness holds the signature
+	if len(capacityGroup) > 0 {
+		tx.Witnesses[capacityGroup[0]] = []byte{}
+		group = append(group, capacityGroup...)
+	}
+	return tx, group, witnessArgs, nil
+}
@@ -0,0 +1,176 @@
+package udt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ququzone/ckb-sdk-go/rpc"
+	"github.com/ququzone/ckb-sdk-go/transaction"
+	"github.com/ququzone/ckb-sdk-go/types"
+	"github.com/ququzone/ckb-sdk-go/utils"
+)
+
+// AmountLength is the length of the little-endian uint128 amount at the beginning of cell data
+const AmountLength = 16
+
+// OptionAmount is the CollectResult.Options key of collected UDT amount
+const OptionAmount = "udt_amount"
+
+var (
+	ErrUnknownNetwork      = errors.New("simple UDT is not deployed on network")
+	ErrInvalidAmount       = errors.New("invalid UDT amount")
+	ErrInsufficientBalance = errors.New("insufficient UDT balance")
+)
+
+var maxAmount = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+
+// ParseAmount decodes the amount from cell data, data after the first 16 bytes is ignored
+func ParseAmount(data []byte) (*big.Int, error) {
+	if len(data) < AmountLength {
+		return nil, fmt.Errorf("%w: data length %d", ErrInvalidAmount, len(data))
+	}
+	be := make([]byte, AmountLength)
+	for i := 0; i < AmountLength; i++ {
+		be[i] = data[AmountLength-1-i]
+	}
+	return new(big.Int).SetBytes(be), nil
+}
+
+// AmountBytes encodes amount as cell data
+func AmountBytes(amount *big.Int) ([]byte, error) {
+	if amount.Sign() < 0 || amount.Cmp(maxAmount) > 0 {
+		return nil, fmt.Errorf("%w: %s out of uint128", ErrInvalidAmount, amount.String())
+	}
+	be := amount.Bytes()
+	data := make([]byte, AmountLength)
+	for i := 0; i < len(be); i++ {
+		data[i] = be[len(be)-1-i]
+	}
+	return data, nil
+}
+
+// NewTypeScript returns type script of the UDT issued by owner lock
+func NewTypeScript(scripts *utils.SystemScripts, owner *types.Script) (*types.Script, error) {
+	if scripts.SUDTCell == nil {
+		return nil, ErrUnknownNetwork
+	}
+	hash, err := owner.Hash()
+	if err != nil {
+		return nil, err
+	}
+	return &types.Script{
+		CodeHash: scripts.SUDTCell.CellHash,
+		HashType: types.HashTypeType,
+		Args:     hash.Bytes(),
+	}, nil
+}
+
+// NewSUDTTx returns a transaction with cell deps of secp256k1 lock and simple UDT type
+func NewSUDTTx(scripts *utils.SystemScripts) (*types.Transaction, error) {
+	if scripts.SUDTCell == nil {
+		return nil, ErrUnknownNetwork
+	}
+	tx := transaction.NewSecp256k1SingleSigTx(scripts)
+	tx.CellDeps = append(tx.CellDeps, &types.CellDep{
+		OutPoint: scripts.SUDTCell.OutPoint,
+		DepType:  types.DepTypeCode,
+	})
+	return tx, nil
+}
+
+// AmountCellProcessor sums UDT amount of collected cells into result Options[OptionAmount] and capacity into
+// result Capacity. Client is used to fetch data of cells without Data, it stops when amount reaches Max.
+type AmountCellProcessor struct {
+	Client rpc.Client
+	Max    *big.Int
+}
+
+func NewAmountCellProcessor(client rpc.Client, max *big.Int) *AmountCellProcessor {
+	return &AmountCellProcessor{
+		Client: client,
+		Max:    max,
+	}
+}
+
+func (p *AmountCellProcessor) Process(cell *types.Cell, result *utils.CollectResult) (bool, error) {
+	data := cell.Data
+	if data == nil && cell.OutputDataLen > 0 {
+		if p.Client == nil {
+			return false, errors.New("client is required to fetch cell data")
+		}
+		info, err := p.Client.GetLiveCell(context.Background(), cell.OutPoint, true)
+		if err != nil {
+			return false, fmt.Errorf("get live cell error: %v", err)
+		}
+		if info.Cell == nil || info.Cell.Data == nil {
+			return false, fmt.Errorf("cell %s:%d is not live", cell.OutPoint.TxHash.String(), cell.OutPoint.Index)
+		}
+		data = info.Cell.Data.Content
+	}
+	amount, err := ParseAmount(data)
+	if err != nil {
+		return false, err
+	}
+
+	total, ok := result.Options[OptionAmount].(*big.Int)
+	if !ok {
+		total = big.NewInt(0)
+	}
+	total = new(big.Int).Add(total, amount)
+	result.Options[OptionAmount] = total
+	result.Capacity = result.Capacity + cell.Capacity
+	result.Cells = append(result.Cells, cell)
+	if p.Max != nil && total.Cmp(p.Max) >= 0 {
+		return true, nil
+	}
+	return false, nil
+}
+
+// Balance returns UDT amount of collector lock, collector is copied with type script and processor replaced.
+func Balance(collector *utils.CellCollector, udtType *types.Script) (*big.Int, error) {
+	result, err := collectAmount(collector, udtType, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resultAmount(result), nil
+}
+
+func collectAmount(collector *utils.CellCollector, udtType *types.Script, max *big.Int) (*utils.CollectResult, error) {
+	c := *collector
+	c.TypeScript = udtType
+	c.EmptyData = false
+	c.Processor = NewAmountCellProcessor(collector.Client, max)
+	result, err := c.Collect()
+	if err != nil {
+		return nil, fmt.Errorf("collect cell error: %v", err)
+	}
+	return result, nil
+}
+
+func resultAmount(result *utils.CollectResult) *big.Int {
+	if amount, ok := result.Options[OptionAmount].(*big.Int); ok {
+		return amount
+	}
+	return big.NewInt(0)
+}
+
+// AddOutput appends a UDT cell of amount to lock to tx, the cell capacity is the minimum occupied capacity
+func AddOutput(tx *types.Transaction, lock *types.Script, udtType *types.Script, amount *big.Int) error {
+	if tx == nil {
+		return errors.New("must init transaction first")
+	}
+	data, err := AmountBytes(amount)
+	if err != nil {
+		return err
+	}
+	output := &types.CellOutput{
+		Lock: lock,
+		Type: udtType,
+	}
+	output.Capacity = output.OccupiedCapacity(data)
+	tx.Outputs = append(tx.Outputs, output)
+	tx.OutputsData = append(tx.OutputsData, data)
+	return nil
+}
@@ -0,0 +1,145 @@
+package udt
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ququzone/ckb-sdk-go/transaction"
+	"github.com/ququzone/ckb-sdk-go/types"
+	"github.com/ququzone/ckb-sdk-go/utils"
+)
+
+func testLock(args string) *types.Script {
+	return &types.Script{
+		CodeHash: types.HexToHash(transaction.SECP256K1_BLAKE160_SIGHASH_ALL_TYPE_HASH),
+		HashType: types.HashTypeType,
+		Args:     common.FromHex(args),
+	}
+}
+
+func TestAmount(t *testing.T) {
+	amount, err := ParseAmount(common.FromHex("0x00e40b54020000000000000000000000ff"))
+	assert.Nil(t, err)
+	assert.Equal(t, "10000000000", amount.String())
+
+	data, err := AmountBytes(amount)
+	assert.Nil(t, err)
+	assert.Equal(t, common.FromHex("0x00e40b54020000000000000000000000"), data)
+
+	_, err = ParseAmount([]byte{1, 2, 3})
+	assert.True(t, errors.Is(err, ErrInvalidAmount))
+	_, err = AmountBytes(new(big.Int).Lsh(big.NewInt(1), 128))
+	assert.True(t, errors.Is(err, ErrInvalidAmount))
+	_, err = AmountBytes(big.NewInt(-1))
+	assert.True(t, errors.Is(err, ErrInvalidAmount))
+}
+
+func TestTransfer(t *testing.T) {
+	scripts := &utils.SystemScripts{
+		SecpSingleSigCell: &utils.SystemScriptCell{
+			CellHash: types.HexToHash(transaction.SECP256K1_BLAKE160_SIGHASH_ALL_TYPE_HASH),
+			OutPoint: &types.OutPoint{TxHash: types.HexToHash("0x01"), Index: 0},
+		},
+		SUDTCell: &utils.SystemScriptCell{
+			CellHash: types.HexToHash("0xc5e5dcf215925f7ef4dfaf5f4b4f105bc321c02776d6e7d52a1db3fcd9d011a4"),
+			OutPoint: &types.OutPoint{TxHash: types.HexToHash("0x02"), Index: 0},
+		},
+	}
+	owner := testLock("0xedcda9513fa030ce4308e29245a22c022d0443bb")
+	receiver := testLock("0xb39bbc0b3673c7d36450bc14cfcdad2d559c6c64")
+	udtType, err := NewTypeScript(scripts, owner)
+	assert.Nil(t, err)
+
+	udtCell := func(index uint, amount int64) *types.Cell {
+		data, _ := AmountBytes(big.NewInt(amount))
+		return &types.Cell{
+			Capacity:      14200000000,
+			Lock:          owner,
+			Type:          udtType,
+			OutPoint:      &types.OutPoint{TxHash: types.HexToHash("0x0a"), Index: index},
+			OutputDataLen: AmountLength,
+			Data:          data,
+		}
+	}
+	cells := []*types.Cell{
+		udtCell(0, 300),
+		udtCell(1, 500),
+		{
+			Capacity: 50000000000,
+			Lock:     owner,
+			OutPoint: &types.OutPoint{TxHash: types.HexToHash("0x0b"), Index: 0},
+		},
+	}
+	collector := utils.NewCellCollector(nil, owner, nil)
+	collector.Provider = utils.NewMemoryCellProvider(cells...)
+
+	balance, err := Balance(collector, udtType)
+	assert.Nil(t, err)
+	assert.Equal(t, "800", balance.String())
+
+	transfer, err := NewTransfer(scripts, udtType, owner)
+	assert.Nil(t, err)
+	assert.Nil(t, transfer.AddOutput(receiver, big.NewInt(1000)))
+	_, err = transfer.Build(collector, 1000)
+	assert.True(t, errors.Is(err, ErrInsufficientBalance))
+
+	transfer, err = NewTransfer(scripts, udtType, owner)
+	assert.Nil(t, err)
+	assert.Nil(t, transfer.AddOutput(receiver, big.NewInt(600)))
+	tx, err := transfer.Build(collector, 1000)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 3, len(tx.Inputs))
+	assert.Equal(t, 3, len(tx.Outputs))
+	assert.Equal(t, uint64(14200000000), tx.Outputs[0].Capacity)
+	change, err := ParseAmount(tx.OutputsData[1])
+	assert.Nil(t, err)
+	assert.Equal(t, "200", change.String())
+	assert.True(t, owner.Equals(tx.Outputs[1].Lock))
+	assert.Nil(t, tx.Outputs[2].Type)
+	assert.Equal(t, []int{0, 1, 2}, transfer.group)
+	assert.Equal(t, []byte{}, tx.Witnesses[2])
+
+	inputs := make([]*types.CellOutput, len(cells))
+	for i, cell := range cells {
+		inputs[i] = &types.CellOutput{Capacity: cell.Capacity, Lock: cell.Lock, Type: cell.Type}
+	}
+	assert.Nil(t, transaction.Verify(tx, inputs))
+}
+
+func TestIssue(t *testing.T) {
+	scripts := &utils.SystemScripts{
+		SecpSingleSigCell: &utils.SystemScriptCell{
+			CellHash: types.HexToHash(transaction.SECP256K1_BLAKE160_SIGHASH_ALL_TYPE_HASH),
+			OutPoint: &types.OutPoint{TxHash: types.HexToHash("0x01"), Index: 0},
+		},
+		SUDTCell: &utils.SystemScriptCell{
+			CellHash: types.HexToHash("0xc5e5dcf215925f7ef4dfaf5f4b4f105bc321c02776d6e7d52a1db3fcd9d011a4"),
+			OutPoint: &types.OutPoint{TxHash: types.HexToHash("0x02"), Index: 0},
+		},
+	}
+	owner := testLock("0xedcda9513fa030ce4308e29245a22c022d0443bb")
+	issue, err := NewIssue(scripts, owner)
+	assert.Nil(t, err)
+	assert.Nil(t, issue.AddOutput(owner, big.NewInt(1000000)))
+
+	collector := utils.NewCellCollector(nil, owner, nil)
+	collector.Provider = utils.NewMemoryCellProvider(&types.Cell{
+		Capacity: 50000000000,
+		Lock:     owner,
+		OutPoint: &types.OutPoint{TxHash: types.HexToHash("0x0b"), Index: 0},
+	})
+	tx, err := issue.Build(collector, 1000)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(tx.Inputs))
+	assert.Equal(t, 2, len(tx.Outputs))
+	assert.True(t, issue.Type.Equals(tx.Outputs[0].Type))
+	assert.Equal(t, types.DepTypeCode, tx.CellDeps[1].DepType)
+
+	_, err = NewIssue(&utils.SystemScripts{}, owner)
+	assert.Equal(t, ErrUnknownNetwork, err)
+}
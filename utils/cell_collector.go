@@ -43,6 +43,8 @@ type CellCollector struct {
 	Processor  CellProcessor
 	UseIndex   bool
 	EmptyData  bool
+	// Provider overrides the provider chosen by UseIndex
+	Provider CellProvider
 }
 
 func NewCellCollector(client rpc.Client, lockScript *types.Script, processor CellProcessor) *CellCollector {
@@ -55,58 +57,40 @@ func NewCellCollector(client rpc.Client, lockScript *types.Script, processor Cel
 }
 
 func (c *CellCollector) Collect() (*CollectResult, error) {
-	lockHash, err := c.LockScript.Hash()
-	if err != nil {
-		return nil, err
-	}
-	return c.collectFromBlocks(lockHash)
+	return c.CollectWithContext(context.Background())
 }
 
-func (c *CellCollector) collectFromBlocks(lockHash types.Hash) (*CollectResult, error) {
-	header, err := c.Client.GetTipHeader(context.Background())
-	if err != nil {
-		return nil, err
-	}
+// CollectWithContext collects cells until processor stops, provider exhausts or ctx is done
+func (c *CellCollector) CollectWithContext(ctx context.Context) (*CollectResult, error) {
 	var result CollectResult
 	result.Options = make(map[string]interface{})
-	var start uint64
-	var stop bool
-	for {
-		end := start + 100
-		if end > header.Number {
-			end = header.Number
-			stop = true
-		}
-		cells, err := c.Client.GetCellsByLockHash(context.Background(), lockHash, start, end)
-		if err != nil {
-			return nil, err
-		}
-		for _, cell := range cells {
-			if c.TypeScript != nil {
-				if !c.TypeScript.Equals(cell.Type) {
-					continue
-				}
-			} else {
-				if cell.Type != nil {
-					continue
-				}
+	err := c.provider().Cells(ctx, c.LockScript, func(cell *types.Cell) (bool, error) {
+		if c.TypeScript != nil {
+			if !c.TypeScript.Equals(cell.Type) {
+				return false, nil
 			}
-			if c.EmptyData && cell.OutputDataLen > 0 {
-				continue
-			}
-			s, err := c.Processor.Process(cell, &result)
-			if err != nil {
-				return nil, err
-			}
-			if s {
-				stop = s
-				break
+		} else {
+			if cell.Type != nil {
+				return false, nil
 			}
 		}
-		if stop {
-			break
+		if c.EmptyData && cell.OutputDataLen > 0 {
+			return false, nil
 		}
-		start = end + 1
+		return c.Processor.Process(cell, &result)
+	})
+	if err != nil {
+		return nil, err
 	}
 	return &result, nil
 }
+
+func (c *CellCollector) provider() CellProvider {
+	if c.Provider != nil {
+		return c.Provider
+	}
+	if c.UseIndex {
+		return NewIndexerCellProvider(c.Client)
+	}
+	return NewBlockScanCellProvider(c.Client)
+}
@@ -0,0 +1,148 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ququzone/ckb-sdk-go/rpc"
+	"github.com/ququzone/ckb-sdk-go/types"
+)
+
+// CellProvider iterates live cells of a lock script, iteration stops when fn returns true or an error
+type CellProvider interface {
+	Cells(ctx context.Context, lock *types.Script, fn func(*types.Cell) (bool, error)) error
+}
+
+// IndexerCellProvider provides cells by the node lock hash indexer, the lock hash must be indexed by IndexLockHash first.
+type IndexerCellProvider struct {
+	Client   rpc.Client
+	PageSize uint
+}
+
+func NewIndexerCellProvider(client rpc.Client) *IndexerCellProvider {
+	return &IndexerCellProvider{
+		Client:   client,
+		PageSize: 50,
+	}
+}
+
+func (p *IndexerCellProvider) Cells(ctx context.Context, lock *types.Script, fn func(*types.Cell) (bool, error)) error {
+	lockHash, err := lock.Hash()
+	if err != nil {
+		return err
+	}
+	blockHashes := make(map[uint64]types.Hash)
+	var page uint
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		cells, err := p.Client.GetLiveCellsByLockHash(ctx, lockHash, page, p.PageSize, false)
+		if err != nil {
+			return fmt.Errorf("get live cells error: %v", err)
+		}
+		for _, cell := range cells {
+			blockHash, ok := blockHashes[cell.CreatedBy.BlockNumber]
+			if !ok {
+				hash, err := p.Client.GetBlockHash(ctx, cell.CreatedBy.BlockNumber)
+				if err != nil {
+					return fmt.Errorf("get block hash of %d error: %v", cell.CreatedBy.BlockNumber, err)
+				}
+				blockHash = *hash
+				blockHashes[cell.CreatedBy.BlockNumber] = blockHash
+			}
+			stop, err := fn(&types.Cell{
+				BlockHash: blockHash,
+				Capacity:  cell.CellOutput.Capacity,
+				Lock:      cell.CellOutput.Lock,
+				OutPoint: &types.OutPoint{
+					TxHash: cell.CreatedBy.TxHash,
+					Index:  cell.CreatedBy.Index,
+				},
+				Type:          cell.CellOutput.Type,
+				Cellbase:      cell.Cellbase,
+				OutputDataLen: cell.OutputDataLen,
+			})
+			if err != nil || stop {
+				return err
+			}
+		}
+		if uint(len(cells)) < p.PageSize {
+			return nil
+		}
+		page++
+	}
+}
+
+// BlockScanCellProvider provides cells by scanning blocks from Start to tip with GetCellsByLockHash,
+// it works without indexer but is slow on a long chain.
+type BlockScanCellProvider struct {
+	Client rpc.Client
+	Start  uint64
+	Step   uint64
+}
+
+func NewBlockScanCellProvider(client rpc.Client) *BlockScanCellProvider {
+	return &BlockScanCellProvider{
+		Client: client,
+		Step:   100,
+	}
+}
+
+func (p *BlockScanCellProvider) Cells(ctx context.Context, lock *types.Script, fn func(*types.Cell) (bool, error)) error {
+	lockHash, err := lock.Hash()
+	if err != nil {
+		return err
+	}
+	header, err := p.Client.GetTipHeader(ctx)
+	if err != nil {
+		return err
+	}
+	for start := p.Start; start <= header.Number; start += p.Step + 1 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		end := start + p.Step
+		if end > header.Number {
+			end = header.Number
+		}
+		cells, err := p.Client.GetCellsByLockHash(ctx, lockHash, start, end)
+		if err != nil {
+			return err
+		}
+		for _, cell := range cells {
+			stop, err := fn(cell)
+			if err != nil || stop {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// MemoryCellProvider provides cells from memory, it is useful for tests and offline building
+type MemoryCellProvider struct {
+	Items []*types.Cell
+}
+
+func NewMemoryCellProvider(cells ...*types.Cell) *MemoryCellProvider {
+	return &MemoryCellProvider{
+		Items: cells,
+	}
+}
+
+func (p *MemoryCellProvider) Cells(ctx context.Context, lock *types.Script, fn func(*types.Cell) (bool, error)) error {
+	for _, cell := range p.Items {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !lock.Equals(cell.Lock) {
+			continue
+		}
+		stop, err := fn(cell)
+		if err != nil || stop {
+			return err
+		}
+	}
+	return nil
+}
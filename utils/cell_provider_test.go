@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ququzone/ckb-sdk-go/rpc"
+	"github.com/ququzone/ckb-sdk-go/types"
+)
+
+func testLock(args string) *types.Script {
+	return &types.Script{
+		CodeHash: types.HexToHash("0x9bd7e06f3ecf4be0f2fcd2188b23f1b9fcc88e5d4b65a8637b17723bbda3cce8"),
+		HashType: types.HashTypeType,
+		Args:     common.FromHex(args),
+	}
+}
+
+func testCell(lock *types.Script, capacity uint64, index uint) *types.Cell {
+	return &types.Cell{
+		Capacity: capacity,
+		Lock:     lock,
+		OutPoint: &types.OutPoint{TxHash: types.HexToHash("0x01"), Index: index},
+	}
+}
+
+func TestCollectFromMemory(t *testing.T) {
+	lock := testLock("0x01")
+	withData := testCell(lock, 300, 2)
+	withData.OutputDataLen = 1
+	provider := NewMemoryCellProvider(
+		testCell(lock, 100, 0),
+		testCell(testLock("0x02"), 200, 1),
+		withData,
+		testCell(lock, 400, 3),
+		testCell(lock, 500, 4),
+	)
+
+	collector := NewCellCollector(nil, lock, NewCapacityCellProcessor(450))
+	collector.Provider = provider
+	result, err := collector.Collect()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(500), result.Capacity)
+	assert.Equal(t, 2, len(result.Cells))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = collector.CollectWithContext(ctx)
+	assert.Equal(t, context.Canceled, err)
+}
+
+type liveCellsClient struct {
+	rpc.Client
+	cells []*types.LiveCell
+}
+
+func (c *liveCellsClient) GetLiveCellsByLockHash(ctx context.Context, lockHash types.Hash, page uint, per uint, reverseOrder bool) ([]*types.LiveCell, error) {
+	start := page * per
+	if start >= uint(len(c.cells)) {
+		return nil, nil
+	}
+	end := start + per
+	if end > uint(len(c.cells)) {
+		end = uint(len(c.cells))
+	}
+	return c.cells[start:end], nil
+}
+
+func (c *liveCellsClient) GetBlockHash(ctx context.Context, number uint64) (*types.Hash, error) {
+	hash := types.BytesToHash([]byte{byte(number)})
+	return &hash, nil
+}
+
+func TestIndexerCellProvider(t *testing.T) {
+	lock := testLock("0x01")
+	client := &liveCellsClient{}
+	for i := 0; i < 5; i++ {
+		client.cells = append(client.cells, &types.LiveCell{
+			CellOutput: &types.CellOutput{Capacity: 100, Lock: lock},
+			CreatedBy:  &types.TransactionPoint{BlockNumber: uint64(i), Index: uint(i), TxHash: types.HexToHash("0x01")},
+		})
+	}
+
+	collector := NewCellCollector(client, lock, NewCapacityCellProcessor(0))
+	collector.UseIndex = true
+	collector.Provider = &IndexerCellProvider{Client: client, PageSize: 2}
+	result, err := collector.Collect()
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(result.Cells))
+	assert.Equal(t, uint64(500), result.Capacity)
+	assert.Equal(t, types.BytesToHash([]byte{3}), result.Cells[3].BlockHash)
+	assert.Equal(t, uint(3), result.Cells[3].OutPoint.Index)
+}
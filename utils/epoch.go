@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ququzone/ckb-sdk-go/rpc"
+	"github.com/ququzone/ckb-sdk-go/types"
+)
+
+// EpochDuration is the target duration of an epoch
+const EpochDuration = 4 * time.Hour
+
+// EpochBlockNumber returns the block number at epoch with fraction, blocks of a future epoch are estimated
+// by the length of the current epoch.
+func EpochBlockNumber(ctx context.Context, client rpc.Client, epoch *types.EpochParams) (uint64, error) {
+	current, err := client.GetCurrentEpoch(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("get current epoch error: %v", err)
+	}
+	return epochBlockNumber(ctx, client, current, epoch)
+}
+
+// EpochTime returns the approximate time at epoch with fraction, it is the timestamp of the block when
+// the block is already produced, otherwise it is estimated from tip by the target epoch duration.
+func EpochTime(ctx context.Context, client rpc.Client, epoch *types.EpochParams) (time.Time, error) {
+	current, err := client.GetCurrentEpoch(ctx)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("get current epoch error: %v", err)
+	}
+	number, err := epochBlockNumber(ctx, client, current, epoch)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	tip, err := client.GetTipHeader(ctx)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("get tip header error: %v", err)
+	}
+	if number <= tip.Number {
+		header, err := client.GetHeaderByNumber(ctx, number)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("get block header of %d error: %v", number, err)
+		}
+		return millisToTime(header.Timestamp), nil
+	}
+	blockInterval := EpochDuration / time.Duration(current.Length)
+	return millisToTime(tip.Timestamp).Add(time.Duration(number-tip.Number) * blockInterval), nil
+}
+
+func epochBlockNumber(ctx context.Context, client rpc.Client, current *types.Epoch, epoch *types.EpochParams) (uint64, error) {
+	epoch = epoch.Normalize()
+	info := current
+	if epoch.Number < current.Number {
+		var err error
+		info, err = client.GetEpochByNumber(ctx, epoch.Number)
+		if err != nil {
+			return 0, fmt.Errorf("get epoch %d error: %v", epoch.Number, err)
+		}
+	}
+	start := info.StartNumber
+	if epoch.Number > current.Number {
+		start += (epoch.Number - current.Number) * current.Length
+	}
+	return start + info.Length*epoch.Index/epoch.Length, nil
+}
+
+func millisToTime(timestamp uint64) time.Time {
+	return time.Unix(0, int64(timestamp)*int64(time.Millisecond))
+}
@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ququzone/ckb-sdk-go/rpc"
+	"github.com/ququzone/ckb-sdk-go/types"
+)
+
+type epochClient struct {
+	rpc.Client
+	epochs []*types.Epoch
+	tip    *types.Header
+}
+
+func (c *epochClient) GetCurrentEpoch(ctx context.Context) (*types.Epoch, error) {
+	return c.epochs[len(c.epochs)-1], nil
+}
+
+func (c *epochClient) GetEpochByNumber(ctx context.Context, number uint64) (*types.Epoch, error) {
+	return c.epochs[number], nil
+}
+
+func (c *epochClient) GetTipHeader(ctx context.Context) (*types.Header, error) {
+	return c.tip, nil
+}
+
+func (c *epochClient) GetHeaderByNumber(ctx context.Context, number uint64) (*types.Header, error) {
+	return &types.Header{Number: number, Timestamp: number * 1000}, nil
+}
+
+func TestEpochBlockNumber(t *testing.T) {
+	client := &epochClient{
+		epochs: []*types.Epoch{
+			{Number: 0, StartNumber: 0, Length: 1000},
+			{Number: 1, StartNumber: 1000, Length: 1200},
+		},
+		tip: &types.Header{Number: 1500, Timestamp: 1500000},
+	}
+	ctx := context.Background()
+
+	number, err := EpochBlockNumber(ctx, client, &types.EpochParams{Number: 0, Index: 1, Length: 2})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(500), number)
+
+	number, err = EpochBlockNumber(ctx, client, &types.EpochParams{Number: 1, Index: 1, Length: 4})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1300), number)
+
+	number, err = EpochBlockNumber(ctx, client, &types.EpochParams{Number: 3})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(3400), number)
+
+	at, err := EpochTime(ctx, client, &types.EpochParams{Number: 1, Index: 1, Length: 4})
+	assert.Nil(t, err)
+	assert.Equal(t, time.Unix(1300, 0), at)
+
+	// 4 hours per 1200 blocks after tip
+	at, err = EpochTime(ctx, client, &types.EpochParams{Number: 2})
+	assert.Nil(t, err)
+	assert.Equal(t, time.Unix(1500, 0).Add(700*EpochDuration/1200), at)
+}
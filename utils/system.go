@@ -7,6 +7,47 @@ import (
 	"github.com/ququzone/ckb-sdk-go/types"
 )
 
+const (
+	MainnetGenesisHash = "0x92b197aa1fba0f63633922c61c92375c9c074a93e85963554f5499fe1450d0e5"
+	TestnetGenesisHash = "0x10639e0895502b5688a6be8cf69460d76541bfa4821629d86d62ba0aae3f9606"
+)
+
+// anyoneCanPayCells are deployed after genesis, so they are only known for public networks
+var anyoneCanPayCells = map[string]SystemScriptCell{
+	MainnetGenesisHash: {
+		CellHash: types.HexToHash("0xd369597ff47f29fbc0d47d2e3775370d1250b85140c670e4718af712983a2354"),
+		OutPoint: &types.OutPoint{
+			TxHash: types.HexToHash("0x4153a2014952d7cac45f285ce9a7c5c0c0e1b21f2d378b82ac1433cb11c25c4d"),
+			Index:  0,
+		},
+	},
+	TestnetGenesisHash: {
+		CellHash: types.HexToHash("0x3419a1c09eb2567f6552ee7a8ecffd64155cffe0f1796e6e61ec088d740c1356"),
+		OutPoint: &types.OutPoint{
+			TxHash: types.HexToHash("0xec26b0f85ed839ece5f11c4c4e837ec359f5adc4420410f6453b1f6b60fb96a6"),
+			Index:  0,
+		},
+	},
+}
+
+// sudtCells are the simple UDT type script code cells, the dep type is code
+var sudtCells = map[string]SystemScriptCell{
+	MainnetGenesisHash: {
+		CellHash: types.HexToHash("0x5e7a36a77e68eecc013dfa2fe6a23f3b6c344b04005808694ae6dd45eea4cfd5"),
+		OutPoint: &types.OutPoint{
+			TxHash: types.HexToHash("0xc7813f6a415144643970c2e88e0bb6ca6a8edc5dd7c1022746f628284a9936d5"),
+			Index:  0,
+		},
+	},
+	TestnetGenesisHash: {
+		CellHash: types.HexToHash("0xc5e5dcf215925f7ef4dfaf5f4b4f105bc321c02776d6e7d52a1db3fcd9d011a4"),
+		OutPoint: &types.OutPoint{
+			TxHash: types.HexToHash("0xe12877ebd2c3c364dc46c5c992bcfaf4fee33fa13eebdf82c591fc9825aab769"),
+			Index:  0,
+		},
+	},
+}
+
 type SystemScriptCell struct {
 	CellHash types.Hash
 	OutPoint *types.OutPoint
@@ -16,6 +57,10 @@ type SystemScripts struct {
 	SecpSingleSigCell *SystemScriptCell
 	SecpMultiSigCell  *SystemScriptCell
 	DaoCell           *SystemScriptCell
+	// ACPCell is the anyone-can-pay lock dep group, nil on dev chains unless set by caller
+	ACPCell *SystemScriptCell
+	// SUDTCell is the simple UDT type script code cell, nil on dev chains unless set by caller
+	SUDTCell *SystemScriptCell
 }
 
 func NewSystemScripts(client rpc.Client) (*SystemScripts, error) {
@@ -37,7 +82,7 @@ func NewSystemScripts(client rpc.Client) (*SystemScripts, error) {
 		return nil, err
 	}
 
-	return &SystemScripts{
+	scripts := &SystemScripts{
 		SecpSingleSigCell: &SystemScriptCell{
 			CellHash: secpHash,
 			OutPoint: &types.OutPoint{
@@ -59,5 +104,26 @@ func NewSystemScripts(client rpc.Client) (*SystemScripts, error) {
 				Index:  2,
 			},
 		},
-	}, nil
+	}
+	if cell, ok := anyoneCanPayCells[genesis.Header.Hash.String()]; ok {
+		scripts.ACPCell = &SystemScriptCell{
+			CellHash: cell.CellHash,
+			OutPoint: &types.OutPoint{
+				TxHash: cell.OutPoint.TxHash,
+				Index:  cell.OutPoint.Index,
+			},
+		}
+	}
+
+	if cell, ok := sudtCells[genesis.Header.Hash.String()]; ok {
+		scripts.SUDTCell = &SystemScriptCell{
+			CellHash: cell.CellHash,
+			OutPoint: &types.OutPoint{
+				TxHash: cell.OutPoint.TxHash,
+				Index:  cell.OutPoint.Index,
+			},
+		}
+	}
+
+	return scripts, nil
 }